@@ -0,0 +1,295 @@
+// Package asm assembles and disassembles EVM bytecode, mirroring early
+// go-ethereum's asm.go: a small enough representation that test
+// fixtures can be written as instruction lists instead of hand-encoded
+// byte strings.
+package asm
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"evm-from-scratch-go/opcodes"
+)
+
+// mnemonicToOpCode is the inverse of opcodes.Mnemonics, used to resolve
+// the instruction names Assemble accepts.
+var mnemonicToOpCode = invertMnemonics()
+
+func invertMnemonics() map[string]opcodes.OpCode {
+	m := make(map[string]opcodes.OpCode, len(opcodes.Mnemonics))
+	for op, name := range opcodes.Mnemonics {
+		m[name] = op
+	}
+	return m
+}
+
+// node is one assembled unit. Most nodes are a fixed run of bytes - an
+// opcode, an opcode plus its explicit operand, or a literal auto-pushed
+// as the smallest PUSH that fits it. A node with a non-empty label
+// instead represents "PUSH <label's address>; JUMP/JUMPI", whose
+// address - and therefore whose PUSH size - isn't known until labels
+// are resolved.
+type node struct {
+	fixed  []byte
+	label  string
+	jumpOp opcodes.OpCode
+	size   int // assumed PUSH size, for label nodes only
+}
+
+// Assemble encodes a sequence of instructions into EVM bytecode.
+// Instructions may be:
+//
+//   - a mnemonic ("ADD", "PUSH1", "JUMPDEST", ...); PUSH1-PUSH32
+//     consume the following instruction as their operand
+//   - an int or a "0x..." hex string, auto-encoded as the smallest PUSH
+//     that fits it, unless consumed as a PUSHn operand above
+//   - a raw []byte, appended to the output as-is
+//   - a label definition ("loop:"), which marks the current offset
+//   - a label reference ("JUMP loop", "JUMPI loop"), which resolves to
+//     "PUSH <loop's offset>; JUMP"/"JUMPI" once every label's address
+//     is known
+//
+// Label references may forward-reference a label defined later in the
+// instruction list.
+func Assemble(instructions ...interface{}) ([]byte, error) {
+	nodes, labelPos, err := parse(instructions)
+	if err != nil {
+		return nil, err
+	}
+	if err := resolveLabels(nodes, labelPos); err != nil {
+		return nil, err
+	}
+	return emit(nodes, labelPos), nil
+}
+
+func parse(instructions []interface{}) ([]node, map[string]int, error) {
+	var nodes []node
+	labelPos := make(map[string]int)
+
+	for i := 0; i < len(instructions); i++ {
+		switch v := instructions[i].(type) {
+		case []byte:
+			nodes = append(nodes, node{fixed: append([]byte(nil), v...)})
+
+		case int:
+			raw, err := autoPush(intBytes(v))
+			if err != nil {
+				return nil, nil, err
+			}
+			nodes = append(nodes, node{fixed: raw})
+
+		case string:
+			switch {
+			case strings.HasSuffix(v, ":") && !strings.Contains(v, " "):
+				labelPos[strings.TrimSuffix(v, ":")] = len(nodes)
+
+			case strings.HasPrefix(v, "0x"):
+				b, err := parseHex(v)
+				if err != nil {
+					return nil, nil, err
+				}
+				raw, err := autoPush(b)
+				if err != nil {
+					return nil, nil, err
+				}
+				nodes = append(nodes, node{fixed: raw})
+
+			case strings.Contains(v, " "):
+				parts := strings.SplitN(v, " ", 2)
+				op, ok := mnemonicToOpCode[parts[0]]
+				if !ok || (op != opcodes.Jump && op != opcodes.Jumpi) {
+					return nil, nil, fmt.Errorf("asm: unsupported instruction %q", v)
+				}
+				nodes = append(nodes, node{label: parts[1], jumpOp: op, size: 1})
+
+			default:
+				n, err := parseMnemonic(v, instructions, &i)
+				if err != nil {
+					return nil, nil, err
+				}
+				nodes = append(nodes, n)
+			}
+
+		default:
+			return nil, nil, fmt.Errorf("asm: unsupported instruction %v (%T)", v, v)
+		}
+	}
+
+	return nodes, labelPos, nil
+}
+
+// parseMnemonic handles a bare mnemonic, consuming instructions[*i+1]
+// as its operand if it names a PUSH1-PUSH32 opcode.
+func parseMnemonic(name string, instructions []interface{}, i *int) (node, error) {
+	op, ok := mnemonicToOpCode[name]
+	if !ok {
+		return node{}, fmt.Errorf("asm: unknown mnemonic %q", name)
+	}
+
+	size, isPush := opcodes.PushOpcodeToBytes[op]
+	if !isPush {
+		return node{fixed: []byte{byte(op)}}, nil
+	}
+
+	if *i+1 >= len(instructions) {
+		return node{}, fmt.Errorf("asm: %s requires an operand", name)
+	}
+	*i++
+	raw, err := operandBytes(instructions[*i])
+	if err != nil {
+		return node{}, fmt.Errorf("asm: %s operand: %w", name, err)
+	}
+	fitted, err := fitToSize(raw, size)
+	if err != nil {
+		return node{}, fmt.Errorf("asm: %s operand: %w", name, err)
+	}
+	return node{fixed: append([]byte{byte(op)}, fitted...)}, nil
+}
+
+// operandBytes reads an explicit PUSHn operand as raw big-endian bytes.
+func operandBytes(v interface{}) ([]byte, error) {
+	switch t := v.(type) {
+	case []byte:
+		return t, nil
+	case int:
+		return intBytes(t), nil
+	case string:
+		if strings.HasPrefix(t, "0x") {
+			return parseHex(t)
+		}
+	}
+	return nil, fmt.Errorf("invalid operand %v (%T)", v, v)
+}
+
+func intBytes(v int) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(v))
+	return buf[:]
+}
+
+func parseHex(s string) ([]byte, error) {
+	s = strings.TrimPrefix(s, "0x")
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("asm: invalid hex literal %q: %w", s, err)
+	}
+	return b, nil
+}
+
+// trimLeadingZeros drops leading zero bytes, keeping at least one byte.
+// An empty input is treated as the value zero, the same as a single
+// zero byte would be.
+func trimLeadingZeros(b []byte) []byte {
+	if len(b) == 0 {
+		return []byte{0}
+	}
+	i := 0
+	for i < len(b)-1 && b[i] == 0 {
+		i++
+	}
+	return b[i:]
+}
+
+// autoPush encodes raw as the smallest PUSH instruction that fits it.
+func autoPush(raw []byte) ([]byte, error) {
+	raw = trimLeadingZeros(raw)
+	if len(raw) > 32 {
+		return nil, fmt.Errorf("asm: value does not fit in 32 bytes")
+	}
+	op := opcodes.Push1 + opcodes.OpCode(len(raw)-1)
+	return append([]byte{byte(op)}, raw...), nil
+}
+
+// fitToSize left-pads raw with zeros to exactly n bytes, or errors if
+// raw is too large to fit.
+func fitToSize(raw []byte, n int) ([]byte, error) {
+	raw = trimLeadingZeros(raw)
+	if len(raw) > n {
+		return nil, fmt.Errorf("value does not fit in %d byte(s)", n)
+	}
+	out := make([]byte, n)
+	copy(out[n-len(raw):], raw)
+	return out, nil
+}
+
+// nodeSize returns the number of bytes n encodes to under its current
+// assumed size.
+func nodeSize(n node) int {
+	if n.fixed != nil {
+		return len(n.fixed)
+	}
+	return 1 + n.size + 1 // PUSHn opcode + address + JUMP/JUMPI opcode
+}
+
+// offsets returns the byte offset of each node, plus a final entry for
+// the offset one past the last node.
+func offsets(nodes []node) []int {
+	offs := make([]int, len(nodes)+1)
+	for i, n := range nodes {
+		offs[i+1] = offs[i] + nodeSize(n)
+	}
+	return offs
+}
+
+// sizeFor returns the smallest number of bytes that can hold target.
+func sizeFor(target int) int {
+	size := 1
+	for v := target >> 8; v != 0; v >>= 8 {
+		size++
+	}
+	return size
+}
+
+// resolveLabels grows each label node's assumed PUSH size until every
+// label's resolved address fits it and the layout stops changing.
+// Growing a node only ever makes earlier offsets larger, never smaller,
+// so this always converges.
+func resolveLabels(nodes []node, labelPos map[string]int) error {
+	for iter := 0; iter <= 32; iter++ {
+		offs := offsets(nodes)
+		changed := false
+		for i := range nodes {
+			if nodes[i].fixed != nil {
+				continue
+			}
+			pos, ok := labelPos[nodes[i].label]
+			if !ok {
+				return fmt.Errorf("asm: undefined label %q", nodes[i].label)
+			}
+			if needed := sizeFor(offs[pos]); needed > nodes[i].size {
+				nodes[i].size = needed
+				changed = true
+			}
+		}
+		if !changed {
+			return nil
+		}
+	}
+	return fmt.Errorf("asm: label offsets did not stabilize")
+}
+
+func emit(nodes []node, labelPos map[string]int) []byte {
+	offs := offsets(nodes)
+	var out []byte
+	for _, n := range nodes {
+		if n.fixed != nil {
+			out = append(out, n.fixed...)
+			continue
+		}
+		target := offs[labelPos[n.label]]
+		out = append(out, byte(opcodes.Push1+opcodes.OpCode(n.size-1)))
+		buf := make([]byte, n.size)
+		for i, v := n.size-1, target; i >= 0; i-- {
+			buf[i] = byte(v)
+			v >>= 8
+		}
+		out = append(out, buf...)
+		out = append(out, byte(n.jumpOp))
+	}
+	return out
+}
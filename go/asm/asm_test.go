@@ -0,0 +1,38 @@
+package asm
+
+import (
+	"testing"
+
+	"evm-from-scratch-go/opcodes"
+)
+
+func TestAssembleAutoPushesSmallestFittingPush(t *testing.T) {
+	code, err := Assemble(1, 2, "ADD")
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+	want := []byte{byte(opcodes.Push1), 1, byte(opcodes.Push1), 2, byte(opcodes.Add)}
+	if string(code) != string(want) {
+		t.Fatalf("code = %x, want %x", code, want)
+	}
+}
+
+func TestAssembleEmptyHexLiteralPushesZero(t *testing.T) {
+	// "0x" parses to an empty byte slice; it must auto-push as PUSH1 0x00
+	// rather than underflowing len(raw)-1 into a bogus opcode.
+	code, err := Assemble("0x")
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+	want := []byte{byte(opcodes.Push1), 0x00}
+	if string(code) != string(want) {
+		t.Fatalf("code = %x, want %x", code, want)
+	}
+}
+
+func TestTrimLeadingZerosOnEmptyInput(t *testing.T) {
+	got := trimLeadingZeros(nil)
+	if len(got) != 1 || got[0] != 0 {
+		t.Fatalf("trimLeadingZeros(nil) = %v, want [0]", got)
+	}
+}
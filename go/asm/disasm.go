@@ -0,0 +1,42 @@
+package asm
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"evm-from-scratch-go/opcodes"
+)
+
+// Disassemble walks code and returns one line per instruction: the
+// mnemonic for plain opcodes, "PUSHn 0x<hex>" for push opcodes with
+// their immediate bytes, and "INVALID 0x<hex>" for bytes that don't
+// correspond to any recognized opcode.
+func Disassemble(code []byte) ([]string, error) {
+	var lines []string
+
+	for pc := 0; pc < len(code); {
+		b := code[pc]
+
+		op, err := opcodes.NewOpCode(b)
+		if err != nil {
+			lines = append(lines, fmt.Sprintf("INVALID 0x%02x", b))
+			pc++
+			continue
+		}
+
+		if n, ok := opcodes.PushOpcodeToBytes[op]; ok {
+			end := pc + 1 + n
+			if end > len(code) {
+				end = len(code)
+			}
+			lines = append(lines, fmt.Sprintf("%s 0x%s", opcodes.Mnemonics[op], hex.EncodeToString(code[pc+1:end])))
+			pc = end
+			continue
+		}
+
+		lines = append(lines, opcodes.Mnemonics[op])
+		pc++
+	}
+
+	return lines, nil
+}
@@ -1,80 +1,167 @@
 package evm
 
 import (
-	"evm-from-scratch-go/opcodes"
-	"math/big"
+	"errors"
+	"maps"
 	"slices"
+
+	"github.com/holiman/uint256"
+
+	"evm-from-scratch-go/gas"
+	"evm-from-scratch-go/opcodes"
+	"evm-from-scratch-go/state"
+	"evm-from-scratch-go/tracing"
 )
 
-// Evm executes the EVM code and returns the stack and a success indicator.
-// It takes the EVM code as input and returns the stack and a success indicator.
-// The stack is returned in reverse order, with the top element at the end.
-// The success indicator is true if the execution was successful, false otherwise.
-func Evm(code []byte) ([]*big.Int, bool) {
-	var stack []*big.Int
-	pc := 0
+// ErrExecutionFailed is returned when execution halts for any reason
+// other than running out of gas: an unknown opcode, an unimplemented
+// opcode, a stack-height violation, or REVERT.
+var ErrExecutionFailed = errors.New("execution failed")
 
-	for pc < len(code) {
-		opcode, err := opcodes.NewOpCode(code[pc])
+// defaultCalculator prices every opcode under the London gas schedule.
+var defaultCalculator = gas.NewCalculator(gas.London)
 
-		if err != nil {
-			return nil, false // Revert on unknown opcodes
-		}
+// defaultJumpTable is the dispatch table used by Evm. It is built once
+// at package init, since it is the same for every execution.
+var defaultJumpTable = newJumpTable(defaultCalculator)
+
+// traceDepth is the call depth reported to tracers. Evm leaves
+// ctx.Invoke nil, so CALL/CREATE-family opcodes never run a callee
+// through this loop, and every traced step is at the top-level frame.
+const traceDepth = 0
 
-		pc++
+// Evm executes contract code against the given call, block, and
+// transaction environment and world state, starting with gasLimit
+// available. It returns the final stack, the gas left over, and an
+// error - gas.ErrOutOfGas if gasLimit ran out, ErrExecutionFailed if
+// execution otherwise failed, or nil on success. The stack is returned
+// top first: the top element is at index 0.
+//
+// Every abnormal termination burns all remaining gas, per the EVM's
+// exceptional-halt rule, except REVERT - the one failure mode that
+// preserves leftover gas for the caller.
+//
+// If tracer is non-nil, it observes every step via its CaptureStart,
+// CaptureState/CaptureFault, and CaptureEnd hooks; it is never called
+// when nil, so tracing costs nothing when no one is watching.
+func Evm(code []byte, gasLimit uint64, call opcodes.CallContext, block opcodes.BlockContext, tx opcodes.TxContext, db state.StateDB, tracer tracing.Tracer) ([]*uint256.Int, uint64, error) {
+	if tracer != nil {
+		tracer.CaptureStart(call.Caller, call.Address, call.CallData, gasLimit, call.Value)
+	}
+
+	ctx := opcodes.NewExecutionContext(code, call, block, tx, db)
+	ctx.Gas = gasLimit
 
-		// Stop execution if the opcodes is STOP
-		if opcode == opcodes.Stop {
-			return stack, true // Halt execution
+	// storage accumulates the slots SSTORE has written so far, for
+	// tracers - state.StateDB has no way to enumerate an account's full
+	// storage, so this is only what tracing has itself observed.
+	storage := tracing.Storage{}
+
+	for ctx.PC < len(code) && !ctx.Halted {
+		opcode, err := opcodes.NewOpCode(code[ctx.PC])
+		if err != nil {
+			if tracer != nil {
+				tracer.CaptureFault(ctx.PC, opcodes.OpCode(code[ctx.PC]), ctx.Gas, 0, traceDepth, ErrExecutionFailed)
+				tracer.CaptureEnd(ctx.Returned, gasLimit, ErrExecutionFailed)
+			}
+			return nil, 0, ErrExecutionFailed // Revert on unknown opcodes - exceptional halts burn all remaining gas
 		}
 
-		if !executeOpcode(&pc, &stack, code, opcode) {
-			return nil, false
+		op := defaultJumpTable[opcode]
+		if op == nil {
+			if tracer != nil {
+				tracer.CaptureFault(ctx.PC, opcode, ctx.Gas, 0, traceDepth, ErrExecutionFailed)
+				tracer.CaptureEnd(ctx.Returned, gasLimit, ErrExecutionFailed)
+			}
+			return nil, 0, ErrExecutionFailed // Revert on unimplemented opcodes (e.g. INVALID) - burns all remaining gas
 		}
-	}
 
-	// Reverse the stack so that the top element is at the end.
-	slices.Reverse(stack)
+		stackLen := ctx.Stack.Len()
+		if stackLen < op.minStack || stackLen > op.maxStack {
+			if tracer != nil {
+				tracer.CaptureFault(ctx.PC, opcode, ctx.Gas, 0, traceDepth, ErrExecutionFailed)
+				tracer.CaptureEnd(ctx.Returned, gasLimit, ErrExecutionFailed)
+			}
+			return nil, 0, ErrExecutionFailed // stack-height violation burns all remaining gas
+		}
 
-	return stack, true // Success
-}
+		var memSize uint64
+		if op.memorySize != nil {
+			memSize = op.memorySize(ctx)
+		}
 
-// executeOpcode executes the opcode and returns true if the execution was successful, false otherwise.
-// It takes the program counter, stack, EVM code, and opcode as input.
-// It executes the opcode and returns true if the execution was successful, false otherwise.
-func executeOpcode(pc *int, stack *[]*big.Int, code []byte, opcode opcodes.OpCode) bool {
-	switch opcode {
-	case opcodes.Push0:
-		*stack = append(*stack, big.NewInt(0)) // Push 0 onto the stack
-	case opcodes.Push1, opcodes.Push2, opcodes.Push4, opcodes.Push6, opcodes.Push10, opcodes.Push11, opcodes.Push32:
-		opcodes.PushX(pc, stack, code, opcodes.PushOpcodeToBytes[opcode])
-	case opcodes.Pop:
-		if _, ok := opcodes.PopX(pc, stack, 1); !ok {
-			return false
+		cost := op.constantGas
+		if op.dynamicGas != nil {
+			dynamicCost, err := op.dynamicGas(ctx)
+			if err != nil {
+				if tracer != nil {
+					tracer.CaptureFault(ctx.PC, opcode, ctx.Gas, cost, traceDepth, err)
+					tracer.CaptureEnd(ctx.Returned, gasLimit, err)
+				}
+				return nil, 0, err
+			}
+			cost += dynamicCost
 		}
-	case opcodes.Add, opcodes.Sub, opcodes.Mul, opcodes.Div, opcodes.Mod, opcodes.Addmod, opcodes.Mulmod, opcodes.Exp:
-		if !opcodes.ApplyArithmeticOp(opcode, pc, stack) {
-			return false
+		if memSize > uint64(ctx.Memory.Len()) {
+			cost += defaultCalculator.MemoryGas(uint64(ctx.Memory.Len()), memSize)
 		}
-	case opcodes.Signextend:
-		if !opcodes.SignedExtend(pc, stack) {
-			return false
+		if ctx.Gas < cost {
+			if tracer != nil {
+				tracer.CaptureFault(ctx.PC, opcode, ctx.Gas, cost, traceDepth, gas.ErrOutOfGas)
+				tracer.CaptureEnd(ctx.Returned, gasLimit, gas.ErrOutOfGas)
+			}
+			return nil, 0, gas.ErrOutOfGas
 		}
-	case opcodes.Sdiv:
-		if !opcodes.SignedDivision(pc, stack) {
-			return false
+
+		if tracer != nil {
+			mem := ctx.Memory.Get(0, uint64(ctx.Memory.Len()))
+			tracer.CaptureState(ctx.PC, opcode, ctx.Gas, cost, ctx.Stack.Data(), mem, maps.Clone(storage), traceDepth)
 		}
-	case opcodes.Smod:
-		if !opcodes.SignedModulus(pc, stack) {
-			return false
+
+		if opcode == opcodes.Sstore {
+			if key, ok := ctx.Peek(0); ok {
+				if value, ok := ctx.Peek(1); ok {
+					storage[opcodes.HashFromWord(key)] = opcodes.HashFromWord(value)
+				}
+			}
 		}
-	case opcodes.Lt, opcodes.Gt, opcodes.Slt, opcodes.Sgt:
-		if !opcodes.ApplyComparisonOp(opcode, pc, stack) {
-			return false
+
+		ctx.Gas -= cost
+
+		if !op.execute(ctx) {
+			if tracer != nil {
+				tracer.CaptureFault(ctx.PC, opcode, ctx.Gas, cost, traceDepth, ErrExecutionFailed)
+				tracer.CaptureEnd(ctx.Returned, gasLimit, ErrExecutionFailed)
+			}
+			return nil, 0, ErrExecutionFailed // opcode-internal validation failure burns all remaining gas
+		}
+	}
+
+	if ctx.Reverted {
+		if tracer != nil {
+			tracer.CaptureEnd(ctx.Returned, gasLimit-ctx.Gas, ErrExecutionFailed)
 		}
-	default:
-		return false // Revert on unknown opcodes
+		return nil, ctx.Gas, ErrExecutionFailed
+	}
+
+	refundCap := (gasLimit - ctx.Gas) / defaultCalculator.RefundQuotient()
+	refund := ctx.Refund()
+	if refund > refundCap {
+		refund = refundCap
+	}
+	ctx.Gas += refund
+
+	// Reverse the bottom-first stack so that the top element comes first.
+	data := ctx.Stack.Data()
+	result := make([]*uint256.Int, len(data))
+	for i := range data {
+		result[i] = &data[i]
+	}
+	slices.Reverse(result)
+
+	if tracer != nil {
+		tracer.CaptureEnd(ctx.Returned, gasLimit-ctx.Gas, nil)
 	}
 
-	return true
+	return result, ctx.Gas, nil // Success
 }
@@ -0,0 +1,67 @@
+package evm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/holiman/uint256"
+
+	"evm-from-scratch-go/asm"
+	"evm-from-scratch-go/opcodes"
+	"evm-from-scratch-go/state"
+)
+
+func run(t *testing.T, code []byte, gasLimit uint64) ([]*uint256.Int, uint64, error) {
+	t.Helper()
+	return Evm(code, gasLimit, opcodes.CallContext{}, opcodes.BlockContext{}, opcodes.TxContext{}, state.NewMemoryState(), nil)
+}
+
+func TestPushThenAdd(t *testing.T) {
+	code, err := asm.Assemble("PUSH1", 1, "PUSH1", 2, "ADD", "STOP")
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+
+	stack, _, err := run(t, code, 100_000)
+	if err != nil {
+		t.Fatalf("Evm: %v", err)
+	}
+	if len(stack) != 1 || stack[0].Uint64() != 3 {
+		t.Fatalf("stack = %v, want [3]", stack)
+	}
+}
+
+func TestStackOverflowHaltsExceptionallyAndBurnsAllGas(t *testing.T) {
+	instructions := []interface{}{"PUSH1", 1}
+	for i := 0; i < maxStackSize+1; i++ {
+		instructions = append(instructions, "DUP1")
+	}
+	code, err := asm.Assemble(instructions...)
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+
+	_, gasLeft, err := run(t, code, 10_000_000)
+	if !errors.Is(err, ErrExecutionFailed) {
+		t.Fatalf("err = %v, want ErrExecutionFailed", err)
+	}
+	if gasLeft != 0 {
+		t.Fatalf("gasLeft = %d, want 0 on an exceptional halt", gasLeft)
+	}
+}
+
+func TestStackUnderflowFaults(t *testing.T) {
+	// ADD with nothing on the stack.
+	code, err := asm.Assemble("ADD")
+	if err != nil {
+		t.Fatalf("Assemble: %v", err)
+	}
+
+	_, gasLeft, err := run(t, code, 100_000)
+	if !errors.Is(err, ErrExecutionFailed) {
+		t.Fatalf("err = %v, want ErrExecutionFailed", err)
+	}
+	if gasLeft != 0 {
+		t.Fatalf("gasLeft = %d, want 0 on an exceptional halt", gasLeft)
+	}
+}
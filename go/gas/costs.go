@@ -0,0 +1,140 @@
+package gas
+
+import "evm-from-scratch-go/opcodes"
+
+// Fixed per-opcode costs, named the way go-ethereum names them.
+const (
+	quickStep   = 2
+	fastestStep = 3
+	fastStep    = 5
+	midStep     = 8
+	slowStep    = 10
+	extStep     = 20
+)
+
+// EIP-150/EIP-2200/EIP-2929/EIP-3529 costs.
+const (
+	accountAccessGasPre2929 = 700
+
+	coldSloadCost         = 2100
+	coldAccountAccessCost = 2600
+	warmStorageReadCost   = 100
+
+	sstoreSentryGas           = 2300
+	sstoreSetGas              = 20000
+	sstoreResetGas            = 5000
+	sstoreClearsRefundPre3529 = 15000
+	sstoreClearsRefundEIP3529 = 4800
+
+	createGas            = 32000
+	callValueTransferGas = 9000
+	callNewAccountGas    = 25000
+
+	keccak256Gas     = 30
+	keccak256WordGas = 6
+	copyWordGas      = 3
+
+	logGas      = 375
+	logTopicGas = 375
+	logDataGas  = 8
+
+	selfdestructGasEIP150 = 5000
+
+	jumpdestGas = 1
+	// tstoreGas is EIP-1153's flat cost for TLOAD/TSTORE: transient
+	// storage has no cold/warm distinction, since it never survives
+	// past the transaction.
+	tstoreGas = 100
+)
+
+// calculator is the Calculator this interpreter ships. It covers
+// Frontier through London; the Hardfork it was built for gates which of
+// the later repricings apply. Frontier and TangerineWhistle are priced
+// identically, with EIP-150's costs; no pre-EIP-150 pricing is applied.
+type calculator struct {
+	hardfork Hardfork
+}
+
+// NewCalculator returns a Calculator enforcing hf's gas rules.
+func NewCalculator(hf Hardfork) Calculator {
+	return &calculator{hardfork: hf}
+}
+
+func (c *calculator) Hardfork() Hardfork { return c.hardfork }
+
+func (c *calculator) RefundQuotient() uint64 {
+	if c.hardfork >= London {
+		return 5
+	}
+	return 2
+}
+
+// accessCostsDynamic reports whether BALANCE/EXTCODE*/SLOAD/CALL-family
+// address and storage access is priced dynamically via EIP-2929
+// cold/warm lookups, rather than a flat constant.
+func (c *calculator) accessCostsDynamic() bool {
+	return c.hardfork >= London
+}
+
+// ConstantGas returns op's fixed cost. Opcodes priced entirely
+// dynamically, and opcodes whose flat cost became a cold/warm lookup
+// under EIP-2929, return 0 here; DynamicGas covers them instead.
+func (c *calculator) ConstantGas(op opcodes.OpCode) uint64 {
+	switch op {
+	case opcodes.Stop, opcodes.Return, opcodes.Revert, opcodes.Exp, opcodes.Sstore:
+		return 0
+	case opcodes.Add, opcodes.Sub, opcodes.Lt, opcodes.Gt, opcodes.Slt, opcodes.Sgt,
+		opcodes.Eq, opcodes.Iszero, opcodes.And, opcodes.Or, opcodes.Xor, opcodes.Not,
+		opcodes.Byte, opcodes.Shl, opcodes.Shr, opcodes.Sar, opcodes.Calldataload:
+		return fastestStep
+	case opcodes.Mul, opcodes.Div, opcodes.Sdiv, opcodes.Mod, opcodes.Smod, opcodes.Signextend:
+		return fastStep
+	case opcodes.Addmod, opcodes.Mulmod, opcodes.Jump:
+		return midStep
+	case opcodes.Jumpi:
+		return slowStep
+	case opcodes.Address, opcodes.Origin, opcodes.Caller, opcodes.Callvalue,
+		opcodes.Calldatasize, opcodes.Codesize, opcodes.Gasprice, opcodes.Coinbase,
+		opcodes.Timestamp, opcodes.Number, opcodes.Difficulty, opcodes.Gaslimit,
+		opcodes.Returndatasize, opcodes.Chainid, opcodes.Basefee, opcodes.Pop,
+		opcodes.Pc, opcodes.Msize, opcodes.Gas, opcodes.Push0:
+		return quickStep
+	case opcodes.Balance, opcodes.Extcodesize, opcodes.Extcodehash, opcodes.Sload,
+		opcodes.Extcodecopy, opcodes.Call, opcodes.Callcode, opcodes.Delegatecall,
+		opcodes.Staticcall:
+		if c.accessCostsDynamic() {
+			return 0
+		}
+		return accountAccessGasPre2929
+	case opcodes.Calldatacopy, opcodes.Codecopy, opcodes.Returndatacopy,
+		opcodes.Mload, opcodes.Mstore, opcodes.Mstore8:
+		return fastestStep
+	case opcodes.Selfbalance:
+		return fastStep
+	case opcodes.Blockhash:
+		return extStep
+	case opcodes.Jumpdest:
+		return jumpdestGas
+	case opcodes.Tload, opcodes.Tstore:
+		return tstoreGas
+	case opcodes.Sha3:
+		return keccak256Gas
+	case opcodes.Log0, opcodes.Log1, opcodes.Log2, opcodes.Log3, opcodes.Log4:
+		return logGas
+	case opcodes.Create, opcodes.Create2:
+		return createGas
+	case opcodes.Selfdestruct:
+		return selfdestructGasEIP150
+	}
+
+	switch {
+	case op >= opcodes.Push1 && op <= opcodes.Push32:
+		return fastestStep
+	case op >= opcodes.Dup1 && op <= opcodes.Dup16:
+		return fastestStep
+	case op >= opcodes.Swap1 && op <= opcodes.Swap16:
+		return fastestStep
+	}
+
+	return 0
+}
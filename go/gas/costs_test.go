@@ -0,0 +1,23 @@
+package gas
+
+import (
+	"testing"
+
+	"evm-from-scratch-go/opcodes"
+)
+
+func TestPush0PricedAtQuickStep(t *testing.T) {
+	calc := NewCalculator(London)
+
+	if got := calc.ConstantGas(opcodes.Push0); got != quickStep {
+		t.Fatalf("PUSH0 ConstantGas = %d, want %d (GasQuickStep per EIP-3855)", got, quickStep)
+	}
+}
+
+func TestPush1PricedAtFastestStep(t *testing.T) {
+	calc := NewCalculator(London)
+
+	if got := calc.ConstantGas(opcodes.Push1); got != fastestStep {
+		t.Fatalf("PUSH1 ConstantGas = %d, want %d", got, fastestStep)
+	}
+}
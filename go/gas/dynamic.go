@@ -0,0 +1,352 @@
+package gas
+
+import (
+	"github.com/holiman/uint256"
+
+	"evm-from-scratch-go/opcodes"
+)
+
+// hugeSize stands in for a stack value too large to fit a uint64. It is
+// far larger than any realistic gas limit, so any cost derived from it
+// reliably exhausts the gas remaining rather than overflowing back
+// around to something small and payable.
+const hugeSize = uint64(1) << 40
+
+// sizeOf reads x as a uint64, substituting hugeSize if x doesn't fit or
+// is larger than hugeSize.
+func sizeOf(x *uint256.Int) uint64 {
+	if x.IsUint64() && x.Uint64() <= hugeSize {
+		return x.Uint64()
+	}
+	return hugeSize
+}
+
+// wordCount returns the number of 32-byte words n bytes occupy,
+// rounding up.
+func wordCount(n uint64) uint64 {
+	return (n + 31) / 32
+}
+
+// memRange returns the highest byte offset a [offset, offset+size) span
+// reaches, for memory-expansion accounting. It is 0 if size is zero,
+// since a zero-size access never expands memory.
+func memRange(offsetX, sizeX *uint256.Int) uint64 {
+	size := sizeOf(sizeX)
+	if size == 0 {
+		return 0
+	}
+	offset := sizeOf(offsetX)
+	if offset > hugeSize-size {
+		return hugeSize
+	}
+	return offset + size
+}
+
+// fixedSize is a throwaway *uint256.Int holding a constant, for reusing
+// memRange against opcodes with a hardcoded access size (MLOAD's 32
+// bytes, MSTORE8's 1 byte, ...).
+func fixedSize(n uint64) *uint256.Int {
+	return new(uint256.Int).SetUint64(n)
+}
+
+// accountIsEmpty approximates "account does not exist yet" the way
+// EIP-161 defines it, using the only signals this interpreter's StateDB
+// exposes: it is empty if it holds no balance and no code. That's the
+// condition CALL's and SELFDESTRUCT's new-account surcharge gate on.
+func accountIsEmpty(ctx *opcodes.ExecutionContext, addr [20]byte) bool {
+	return ctx.State.GetBalance(addr).IsZero() && ctx.State.GetCodeSize(addr) == 0
+}
+
+// MemorySize returns the number of bytes of memory op's stack
+// arguments require it to touch. It must be called before op's execute
+// function pops its arguments off the stack.
+func (c *calculator) MemorySize(op opcodes.OpCode, ctx *opcodes.ExecutionContext) uint64 {
+	peek := func(n int) *uint256.Int {
+		v, ok := ctx.Peek(n)
+		if !ok {
+			return new(uint256.Int)
+		}
+		return v
+	}
+
+	switch op {
+	case opcodes.Mload, opcodes.Mstore:
+		return memRange(peek(0), fixedSize(32))
+	case opcodes.Mstore8:
+		return memRange(peek(0), fixedSize(1))
+	case opcodes.Sha3, opcodes.Log0, opcodes.Log1, opcodes.Log2, opcodes.Log3, opcodes.Log4,
+		opcodes.Return, opcodes.Revert:
+		return memRange(peek(0), peek(1))
+	case opcodes.Calldatacopy, opcodes.Codecopy, opcodes.Returndatacopy:
+		return memRange(peek(0), peek(2))
+	case opcodes.Extcodecopy:
+		return memRange(peek(1), peek(3))
+	case opcodes.Create, opcodes.Create2:
+		return memRange(peek(1), peek(2))
+	case opcodes.Call, opcodes.Callcode:
+		args := memRange(peek(3), peek(4))
+		ret := memRange(peek(5), peek(6))
+		if ret > args {
+			return ret
+		}
+		return args
+	case opcodes.Delegatecall, opcodes.Staticcall:
+		args := memRange(peek(2), peek(3))
+		ret := memRange(peek(4), peek(5))
+		if ret > args {
+			return ret
+		}
+		return args
+	}
+	return 0
+}
+
+// MemoryGas implements the EVM's quadratic memory-expansion formula:
+// 3*words + words^2/512. It charges only the marginal cost of growing
+// from 'from' bytes to 'to' bytes.
+func (c *calculator) MemoryGas(from, to uint64) uint64 {
+	if to <= from {
+		return 0
+	}
+	cost := func(n uint64) uint64 {
+		words := wordCount(n)
+		return 3*words + words*words/512
+	}
+	return cost(to) - cost(from)
+}
+
+// DynamicGas returns op's additional, operand-dependent cost, not
+// including memory expansion (MemorySize/MemoryGas cover that
+// separately). It must be called before op's execute function pops its
+// arguments off the stack.
+func (c *calculator) DynamicGas(op opcodes.OpCode, ctx *opcodes.ExecutionContext) (uint64, error) {
+	peek := func(n int) *uint256.Int {
+		v, ok := ctx.Peek(n)
+		if !ok {
+			return new(uint256.Int)
+		}
+		return v
+	}
+
+	switch op {
+	case opcodes.Exp:
+		exponent := peek(1)
+		byteLen := uint64(exponent.BitLen()+7) / 8
+		return 10 + 50*byteLen, nil
+
+	case opcodes.Sha3:
+		return keccak256WordGas * wordCount(sizeOf(peek(1))), nil
+
+	case opcodes.Calldatacopy, opcodes.Codecopy, opcodes.Returndatacopy:
+		return copyWordGas * wordCount(sizeOf(peek(2))), nil
+
+	case opcodes.Extcodecopy:
+		cost := copyWordGas * wordCount(sizeOf(peek(3)))
+		if c.accessCostsDynamic() {
+			cost += c.addressAccessCost(ctx, opcodes.AddressFromWord(peek(0)))
+		}
+		return cost, nil
+
+	case opcodes.Balance, opcodes.Extcodesize, opcodes.Extcodehash:
+		if !c.accessCostsDynamic() {
+			return 0, nil
+		}
+		return c.addressAccessCost(ctx, opcodes.AddressFromWord(peek(0))), nil
+
+	case opcodes.Sload:
+		if !c.accessCostsDynamic() {
+			return 0, nil
+		}
+		return c.slotAccessCost(ctx, opcodes.HashFromWord(peek(0)), coldSloadCost), nil
+
+	case opcodes.Sstore:
+		return c.sstoreGas(ctx)
+
+	case opcodes.Log0, opcodes.Log1, opcodes.Log2, opcodes.Log3, opcodes.Log4:
+		topics := uint64(op - opcodes.Log0)
+		return topics*logTopicGas + sizeOf(peek(1))*logDataGas, nil
+
+	case opcodes.Call, opcodes.Callcode:
+		return c.callGas(ctx, true), nil
+	case opcodes.Delegatecall, opcodes.Staticcall:
+		return c.callGas(ctx, false), nil
+
+	case opcodes.Create2:
+		return keccak256WordGas * wordCount(sizeOf(peek(2))), nil
+
+	case opcodes.Selfdestruct:
+		return c.selfdestructGas(ctx), nil
+	}
+
+	return 0, nil
+}
+
+// addressAccessCost charges EIP-2929's cold/warm lookup cost for
+// accessing addr, marking it warm as a side effect.
+func (c *calculator) addressAccessCost(ctx *opcodes.ExecutionContext, addr [20]byte) uint64 {
+	if ctx.AccessAddress(addr) {
+		return warmStorageReadCost
+	}
+	return coldAccountAccessCost
+}
+
+// slotAccessCost charges EIP-2929's cold/warm lookup cost for accessing
+// storage slot (ctx.Call.Address, key), marking it warm as a side
+// effect. coldCost is the opcode's own cold-access price (SLOAD and
+// SSTORE charge different ones).
+func (c *calculator) slotAccessCost(ctx *opcodes.ExecutionContext, key [32]byte, coldCost uint64) uint64 {
+	if ctx.AccessSlot(ctx.Call.Address, key) {
+		return warmStorageReadCost
+	}
+	return coldCost
+}
+
+// callGas prices CALL/CALLCODE/DELEGATECALL/STATICCALL's own cost: the
+// EIP-2929 address access (London on; earlier, ConstantGas charges the
+// flat access cost), plus - for CALL and CALLCODE - the value-transfer
+// and possible new-account surcharge. It does not include the gas
+// forwarded to the callee, which applyCallFamily accounts for separately.
+func (c *calculator) callGas(ctx *opcodes.ExecutionContext, hasValue bool) uint64 {
+	peek := func(n int) *uint256.Int {
+		v, ok := ctx.Peek(n)
+		if !ok {
+			return new(uint256.Int)
+		}
+		return v
+	}
+
+	address := opcodes.AddressFromWord(peek(1))
+	var cost uint64
+	if c.accessCostsDynamic() {
+		cost = c.addressAccessCost(ctx, address)
+	}
+
+	if hasValue {
+		value := peek(2)
+		if !value.IsZero() {
+			cost += callValueTransferGas
+			if accountIsEmpty(ctx, address) {
+				cost += callNewAccountGas
+			}
+		}
+	}
+	return cost
+}
+
+// selfdestructGas prices SELFDESTRUCT: EIP-2929's cold-access cost for
+// the beneficiary, plus EIP-161's new-account surcharge if the
+// executing contract has a balance to transfer and the beneficiary
+// doesn't exist yet.
+func (c *calculator) selfdestructGas(ctx *opcodes.ExecutionContext) uint64 {
+	beneficiary, ok := ctx.Peek(0)
+	if !ok {
+		return 0
+	}
+	addr := opcodes.AddressFromWord(beneficiary)
+
+	var cost uint64
+	if c.accessCostsDynamic() && !ctx.AccessAddress(addr) {
+		cost = coldAccountAccessCost
+	}
+	if !ctx.State.GetBalance(ctx.Call.Address).IsZero() && accountIsEmpty(ctx, addr) {
+		cost += callNewAccountGas
+	}
+	return cost
+}
+
+// sstoreGas implements EIP-2200's net gas metering (Istanbul+), EIP-2929's
+// cold storage surcharge (London+), and EIP-3529's reduced clearing
+// refund (London+). Pre-Istanbul hardforks use the original flat rule
+// instead, via sstoreGasLegacy.
+func (c *calculator) sstoreGas(ctx *opcodes.ExecutionContext) (uint64, error) {
+	keyArg, _ := ctx.Peek(0)
+	valueArg, _ := ctx.Peek(1)
+	key := opcodes.HashFromWord(keyArg)
+	value := opcodes.HashFromWord(valueArg)
+	current := ctx.State.GetState(ctx.Call.Address, key)
+
+	if c.hardfork < Istanbul {
+		return c.sstoreGasLegacy(ctx, current, value), nil
+	}
+	if ctx.Gas <= sstoreSentryGas {
+		return 0, ErrOutOfGas
+	}
+
+	// dirtyReadCost is what re-reading an already-touched-this-execution
+	// slot costs: EIP-1884's flat SLOAD_GAS pre-2929, or EIP-2929's warm
+	// access cost from London on.
+	dirtyReadCost := uint64(800)
+	var cold uint64
+	if c.accessCostsDynamic() {
+		dirtyReadCost = warmStorageReadCost
+		if !ctx.AccessSlot(ctx.Call.Address, key) {
+			cold = coldSloadCost
+		}
+	}
+
+	if current == value {
+		return cold + dirtyReadCost, nil
+	}
+
+	original := ctx.OriginalStorage(ctx.Call.Address, key)
+	var zero [32]byte
+
+	// resetCost is SSTORE_RESET_GAS, minus the cold-access surcharge
+	// once that surcharge is billed separately via 'cold'.
+	resetCost := uint64(sstoreResetGas)
+	if c.accessCostsDynamic() {
+		resetCost -= coldSloadCost
+	}
+
+	if original == current {
+		if original == zero {
+			return cold + sstoreSetGas, nil
+		}
+		if value == zero {
+			ctx.AddRefund(c.clearRefund())
+		}
+		return cold + resetCost, nil
+	}
+
+	if original != zero {
+		switch {
+		case current == zero:
+			ctx.SubRefund(c.clearRefund())
+		case value == zero:
+			ctx.AddRefund(c.clearRefund())
+		}
+	}
+	if original == value {
+		if original == zero {
+			ctx.AddRefund(sstoreSetGas - dirtyReadCost)
+		} else {
+			ctx.AddRefund(resetCost - dirtyReadCost)
+		}
+	}
+	return cold + dirtyReadCost, nil
+}
+
+// sstoreGasLegacy implements the original, pre-EIP-2200 SSTORE rule
+// used by Frontier and TangerineWhistle: a flat cost keyed only on
+// whether the slot is being set from or to zero, with no notion of
+// "original" value or of a slot already touched this execution.
+func (c *calculator) sstoreGasLegacy(ctx *opcodes.ExecutionContext, current, value [32]byte) uint64 {
+	var zero [32]byte
+	if current == zero && value != zero {
+		return sstoreSetGas
+	}
+	if current != zero && value == zero {
+		ctx.AddRefund(c.clearRefund())
+	}
+	return sstoreResetGas
+}
+
+// clearRefund returns the refund granted for clearing a storage slot
+// back to zero: EIP-3529 cut this from 15000 to 4800 starting at
+// London, to curb refund-based gas-rebate abuse.
+func (c *calculator) clearRefund() uint64 {
+	if c.hardfork >= London {
+		return sstoreClearsRefundEIP3529
+	}
+	return sstoreClearsRefundPre3529
+}
@@ -0,0 +1,64 @@
+// Package gas computes the EVM's per-opcode gas costs: the fixed
+// constant-gas charged before an opcode runs, the operand-dependent
+// dynamic-gas on top of it, and the cost of expanding volatile memory.
+// It implements the EIP-150, EIP-2200, and EIP-3529 gas schedules.
+package gas
+
+import (
+	"errors"
+
+	"evm-from-scratch-go/opcodes"
+)
+
+// ErrOutOfGas is returned when executing an opcode would cost more gas
+// than remains in the current call frame.
+var ErrOutOfGas = errors.New("out of gas")
+
+// Hardfork identifies which revision of the gas rules a Calculator
+// enforces. Hardforks are additive: each one reprices or extends the
+// rules of the ones before it.
+type Hardfork int
+
+const (
+	// Frontier is the original gas schedule.
+	Frontier Hardfork = iota
+	// TangerineWhistle applies EIP-150's repricing of IO-heavy opcodes.
+	TangerineWhistle
+	// Istanbul applies EIP-2200's net gas metering for SSTORE.
+	Istanbul
+	// London applies EIP-2929's cold/warm access costs and EIP-3529's
+	// reduced gas refunds.
+	London
+)
+
+// Calculator computes the gas cost of executing an opcode under one
+// hardfork's rules. Building a jump table against a different
+// Calculator swaps every opcode's pricing in one place.
+type Calculator interface {
+	// Hardfork reports which rules this Calculator enforces.
+	Hardfork() Hardfork
+
+	// ConstantGas returns op's fixed, operand-independent cost, charged
+	// before it runs. Opcodes priced entirely dynamically (EXP, SSTORE,
+	// ...) return 0 here, as do the EIP-2929 access-priced opcodes
+	// (BALANCE, SLOAD, the CALL family, ...) under London.
+	ConstantGas(op opcodes.OpCode) uint64
+
+	// DynamicGas returns op's additional, operand-dependent cost, not
+	// including memory expansion. It must be called before op's
+	// execute function pops its arguments off the stack.
+	DynamicGas(op opcodes.OpCode, ctx *opcodes.ExecutionContext) (uint64, error)
+
+	// MemorySize returns the number of bytes of memory op's stack
+	// arguments require it to touch, for memory-expansion accounting.
+	// It is 0 for opcodes that don't touch memory.
+	MemorySize(op opcodes.OpCode, ctx *opcodes.ExecutionContext) uint64
+
+	// MemoryGas returns the cost of expanding memory from 'from' bytes
+	// to 'to' bytes. It is 0 if to <= from.
+	MemoryGas(from, to uint64) uint64
+
+	// RefundQuotient returns the divisor applied to gas used when
+	// capping the refund counter at the end of execution.
+	RefundQuotient() uint64
+}
@@ -0,0 +1,314 @@
+package evm
+
+import (
+	"github.com/holiman/uint256"
+
+	"evm-from-scratch-go/gas"
+	"evm-from-scratch-go/opcodes"
+)
+
+// operation describes one opcode's execution function together with the
+// stack-height bounds the interpreter validates before running it, its
+// constant gas cost, and its dynamic-gas and memory-size rules (nil for
+// opcodes priced by constant gas alone). This mirrors go-ethereum's
+// JumpTable entries.
+type operation struct {
+	execute     func(ctx *opcodes.ExecutionContext) bool
+	minStack    int
+	maxStack    int
+	constantGas uint64
+	dynamicGas  func(ctx *opcodes.ExecutionContext) (uint64, error)
+	memorySize  func(ctx *opcodes.ExecutionContext) uint64
+}
+
+// jumpTable is indexed directly by opcode byte value, giving O(1)
+// dispatch in place of a linear switch.
+type jumpTable [256]*operation
+
+const maxStackSize = 1024
+
+// maxStack returns the largest stack height at which an opcode that
+// pops 'pop' items and pushes 'push' may run without the result
+// breaching maxStackSize, mirroring go-ethereum's identically-named
+// helper.
+func maxStack(pop, push int) int {
+	return maxStackSize + pop - push
+}
+
+// newJumpTable builds the jump table for every opcode this interpreter
+// supports, pricing each entry with calc.
+func newJumpTable(calc gas.Calculator) *jumpTable {
+	var table jumpTable
+
+	arith := func(op opcodes.OpCode) func(ctx *opcodes.ExecutionContext) bool {
+		return func(ctx *opcodes.ExecutionContext) bool { return opcodes.ApplyArithmeticOp(op, ctx) }
+	}
+	compare := func(op opcodes.OpCode) func(ctx *opcodes.ExecutionContext) bool {
+		return func(ctx *opcodes.ExecutionContext) bool { return opcodes.ApplyComparisonOp(op, ctx) }
+	}
+	bitwise := func(op opcodes.OpCode) func(ctx *opcodes.ExecutionContext) bool {
+		return func(ctx *opcodes.ExecutionContext) bool { return opcodes.ApplyBitwiseOp(op, ctx) }
+	}
+
+	table[opcodes.Stop] = &operation{
+		execute: func(ctx *opcodes.ExecutionContext) bool {
+			ctx.Halted = true
+			return true
+		},
+		minStack: 0, maxStack: maxStack(0, 0),
+	}
+
+	for _, op := range []opcodes.OpCode{opcodes.Add, opcodes.Sub, opcodes.Mul, opcodes.Div, opcodes.Mod, opcodes.Exp} {
+		table[op] = &operation{execute: arith(op), minStack: 2, maxStack: maxStack(2, 1)}
+	}
+	for _, op := range []opcodes.OpCode{opcodes.Addmod, opcodes.Mulmod} {
+		table[op] = &operation{execute: arith(op), minStack: 3, maxStack: maxStack(3, 1)}
+	}
+	table[opcodes.Sdiv] = &operation{execute: opcodes.SignedDivision, minStack: 2, maxStack: maxStack(2, 1)}
+	table[opcodes.Smod] = &operation{execute: opcodes.SignedModulus, minStack: 2, maxStack: maxStack(2, 1)}
+	table[opcodes.Signextend] = &operation{execute: opcodes.SignedExtend, minStack: 2, maxStack: maxStack(2, 1)}
+
+	for _, op := range []opcodes.OpCode{opcodes.Lt, opcodes.Gt, opcodes.Slt, opcodes.Sgt, opcodes.Eq} {
+		table[op] = &operation{execute: compare(op), minStack: 2, maxStack: maxStack(2, 1)}
+	}
+	table[opcodes.Iszero] = &operation{execute: compare(opcodes.Iszero), minStack: 1, maxStack: maxStack(1, 1)}
+
+	for _, op := range []opcodes.OpCode{opcodes.And, opcodes.Or, opcodes.Xor, opcodes.Byte, opcodes.Shl, opcodes.Shr, opcodes.Sar} {
+		table[op] = &operation{execute: bitwise(op), minStack: 2, maxStack: maxStack(2, 1)}
+	}
+	table[opcodes.Not] = &operation{execute: bitwise(opcodes.Not), minStack: 1, maxStack: maxStack(1, 1)}
+
+	table[opcodes.Sha3] = &operation{execute: opcodes.ApplySha3, minStack: 2, maxStack: maxStack(2, 1)}
+
+	// unaryStack records the pop/push counts of every opcode in the
+	// unary map below, since - unlike the groups above - each one has a
+	// different arity; minStack/maxStack are derived from it rather than
+	// a single constant, per opcode.
+	unaryStack := map[opcodes.OpCode]struct{ pop, push int }{
+		opcodes.Address:        {0, 1},
+		opcodes.Balance:        {1, 1},
+		opcodes.Origin:         {0, 1},
+		opcodes.Caller:         {0, 1},
+		opcodes.Callvalue:      {0, 1},
+		opcodes.Calldataload:   {1, 1},
+		opcodes.Calldatasize:   {0, 1},
+		opcodes.Calldatacopy:   {3, 0},
+		opcodes.Codesize:       {0, 1},
+		opcodes.Codecopy:       {3, 0},
+		opcodes.Gasprice:       {0, 1},
+		opcodes.Extcodesize:    {1, 1},
+		opcodes.Extcodecopy:    {4, 0},
+		opcodes.Returndatasize: {0, 1},
+		opcodes.Returndatacopy: {3, 0},
+		opcodes.Extcodehash:    {1, 1},
+		opcodes.Blockhash:      {1, 1},
+		opcodes.Coinbase:       {0, 1},
+		opcodes.Timestamp:      {0, 1},
+		opcodes.Number:         {0, 1},
+		opcodes.Difficulty:     {0, 1},
+		opcodes.Gaslimit:       {0, 1},
+		opcodes.Chainid:        {0, 1},
+		opcodes.Selfbalance:    {0, 1},
+		opcodes.Basefee:        {0, 1},
+		opcodes.Mload:          {1, 1},
+		opcodes.Mstore:         {2, 0},
+		opcodes.Mstore8:        {2, 0},
+		opcodes.Msize:          {0, 1},
+		opcodes.Sload:          {1, 1},
+		opcodes.Sstore:         {2, 0},
+		opcodes.Tload:          {1, 1},
+		opcodes.Tstore:         {2, 0},
+		opcodes.Jump:           {1, 0},
+		opcodes.Jumpi:          {2, 0},
+		opcodes.Jumpdest:       {0, 0},
+		opcodes.Pc:             {0, 1},
+		opcodes.Create:         {3, 1},
+		opcodes.Create2:        {4, 1},
+		opcodes.Call:           {7, 1},
+		opcodes.Callcode:       {7, 1},
+		opcodes.Delegatecall:   {6, 1},
+		opcodes.Staticcall:     {6, 1},
+		opcodes.Return:         {2, 0},
+		opcodes.Revert:         {2, 0},
+		opcodes.Selfdestruct:   {1, 0},
+	}
+
+	unary := map[opcodes.OpCode]func(ctx *opcodes.ExecutionContext) bool{
+		opcodes.Address:        opcodes.ApplyAddress,
+		opcodes.Balance:        opcodes.ApplyBalance,
+		opcodes.Origin:         opcodes.ApplyOrigin,
+		opcodes.Caller:         opcodes.ApplyCaller,
+		opcodes.Callvalue:      opcodes.ApplyCallvalue,
+		opcodes.Calldataload:   opcodes.ApplyCalldataload,
+		opcodes.Calldatasize:   opcodes.ApplyCalldatasize,
+		opcodes.Calldatacopy:   opcodes.ApplyCalldatacopy,
+		opcodes.Codesize:       opcodes.ApplyCodesize,
+		opcodes.Codecopy:       opcodes.ApplyCodecopy,
+		opcodes.Gasprice:       opcodes.ApplyGasprice,
+		opcodes.Extcodesize:    opcodes.ApplyExtcodesize,
+		opcodes.Extcodecopy:    opcodes.ApplyExtcodecopy,
+		opcodes.Returndatasize: opcodes.ApplyReturndatasize,
+		opcodes.Returndatacopy: opcodes.ApplyReturndatacopy,
+		opcodes.Extcodehash:    opcodes.ApplyExtcodehash,
+		opcodes.Blockhash:      opcodes.ApplyBlockhash,
+		opcodes.Coinbase:       opcodes.ApplyCoinbase,
+		opcodes.Timestamp:      opcodes.ApplyTimestamp,
+		opcodes.Number:         opcodes.ApplyNumber,
+		opcodes.Difficulty:     opcodes.ApplyDifficulty,
+		opcodes.Gaslimit:       opcodes.ApplyGaslimit,
+		opcodes.Chainid:        opcodes.ApplyChainid,
+		opcodes.Selfbalance:    opcodes.ApplySelfbalance,
+		opcodes.Basefee:        opcodes.ApplyBasefee,
+		opcodes.Mload:          opcodes.ApplyMload,
+		opcodes.Mstore:         opcodes.ApplyMstore,
+		opcodes.Mstore8:        opcodes.ApplyMstore8,
+		opcodes.Msize:          opcodes.ApplyMsize,
+		opcodes.Sload:          opcodes.ApplySload,
+		opcodes.Sstore:         opcodes.ApplySstore,
+		opcodes.Tload:          opcodes.ApplyTload,
+		opcodes.Tstore:         opcodes.ApplyTstore,
+		opcodes.Jump:           opcodes.ApplyJump,
+		opcodes.Jumpi:          opcodes.ApplyJumpi,
+		opcodes.Jumpdest:       opcodes.ApplyJumpdest,
+		opcodes.Pc:             opcodes.ApplyPc,
+		opcodes.Create:         opcodes.ApplyCreate,
+		opcodes.Create2:        opcodes.ApplyCreate2,
+		opcodes.Call:           opcodes.ApplyCall,
+		opcodes.Callcode:       opcodes.ApplyCallcode,
+		opcodes.Delegatecall:   opcodes.ApplyDelegatecall,
+		opcodes.Staticcall:     opcodes.ApplyStaticcall,
+		opcodes.Return:         opcodes.ApplyReturn,
+		opcodes.Revert:         opcodes.ApplyRevert,
+		opcodes.Selfdestruct:   opcodes.ApplySelfdestruct,
+	}
+	for op, fn := range unary {
+		d := unaryStack[op]
+		table[op] = &operation{execute: fn, minStack: d.pop, maxStack: maxStack(d.pop, d.push)}
+	}
+
+	table[opcodes.Push0] = &operation{
+		execute: func(ctx *opcodes.ExecutionContext) bool {
+			ctx.Push(new(uint256.Int))
+			ctx.PC++
+			return true
+		},
+		minStack: 0, maxStack: maxStack(0, 1),
+	}
+	for i := 0; i < 32; i++ {
+		op := opcodes.Push1 + opcodes.OpCode(i)
+		size := i + 1
+		table[op] = &operation{
+			execute: func(ctx *opcodes.ExecutionContext) bool {
+				opcodes.PushX(ctx, size)
+				return true
+			},
+			minStack: 0, maxStack: maxStack(0, 1),
+		}
+	}
+
+	table[opcodes.Pop] = &operation{
+		execute: func(ctx *opcodes.ExecutionContext) bool {
+			_, ok := opcodes.PopX(ctx, 1)
+			if ok {
+				ctx.PC++
+			}
+			return ok
+		},
+		minStack: 1, maxStack: maxStack(1, 0),
+	}
+
+	for i := 0; i < 16; i++ {
+		depth := i + 1
+		table[opcodes.Dup1+opcodes.OpCode(i)] = &operation{
+			execute: func(ctx *opcodes.ExecutionContext) bool {
+				ok := opcodes.Dup(ctx, depth)
+				if ok {
+					ctx.PC++
+				}
+				return ok
+			},
+			minStack: depth, maxStack: maxStack(0, 1),
+		}
+		table[opcodes.Swap1+opcodes.OpCode(i)] = &operation{
+			execute: func(ctx *opcodes.ExecutionContext) bool {
+				ok := opcodes.Swap(ctx, depth)
+				if ok {
+					ctx.PC++
+				}
+				return ok
+			},
+			minStack: depth + 1, maxStack: maxStack(0, 0),
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		topics := i
+		table[opcodes.Log0+opcodes.OpCode(i)] = &operation{
+			execute: func(ctx *opcodes.ExecutionContext) bool {
+				return opcodes.ApplyLog(ctx, topics)
+			},
+			minStack: 2 + topics, maxStack: maxStack(2+topics, 0),
+		}
+	}
+
+	table[opcodes.Gas] = &operation{
+		execute: func(ctx *opcodes.ExecutionContext) bool {
+			ctx.Push(new(uint256.Int).SetUint64(ctx.Gas))
+			ctx.PC++
+			return true
+		},
+		minStack: 0, maxStack: maxStack(0, 1),
+	}
+
+	for i, op := range table {
+		if op != nil {
+			op.constantGas = calc.ConstantGas(opcodes.OpCode(i))
+		}
+	}
+
+	// dynamicGasOps are the opcodes whose cost depends on their operands
+	// (EXP's exponent, SHA3/LOG/COPY's byte span, SSTORE's value
+	// transition, the CALL family's access/transfer costs, CREATE2's
+	// init-code hashing, ...) rather than being fully covered by
+	// constantGas.
+	dynamicGasOps := []opcodes.OpCode{
+		opcodes.Exp, opcodes.Sha3,
+		opcodes.Calldatacopy, opcodes.Codecopy, opcodes.Returndatacopy, opcodes.Extcodecopy,
+		opcodes.Balance, opcodes.Extcodesize, opcodes.Extcodehash, opcodes.Sload, opcodes.Sstore,
+		opcodes.Log0, opcodes.Log1, opcodes.Log2, opcodes.Log3, opcodes.Log4,
+		opcodes.Call, opcodes.Callcode, opcodes.Delegatecall, opcodes.Staticcall,
+		opcodes.Create2, opcodes.Selfdestruct,
+	}
+	for _, op := range dynamicGasOps {
+		op := op
+		if table[op] == nil {
+			continue
+		}
+		table[op].dynamicGas = func(ctx *opcodes.ExecutionContext) (uint64, error) {
+			return calc.DynamicGas(op, ctx)
+		}
+	}
+
+	// memorySizeOps are the opcodes whose stack arguments name a span of
+	// memory, so the interpreter must account for memory expansion
+	// before running them.
+	memorySizeOps := []opcodes.OpCode{
+		opcodes.Mload, opcodes.Mstore, opcodes.Mstore8, opcodes.Sha3,
+		opcodes.Log0, opcodes.Log1, opcodes.Log2, opcodes.Log3, opcodes.Log4,
+		opcodes.Calldatacopy, opcodes.Codecopy, opcodes.Returndatacopy, opcodes.Extcodecopy,
+		opcodes.Create, opcodes.Create2,
+		opcodes.Call, opcodes.Callcode, opcodes.Delegatecall, opcodes.Staticcall,
+		opcodes.Return, opcodes.Revert,
+	}
+	for _, op := range memorySizeOps {
+		op := op
+		if table[op] == nil {
+			continue
+		}
+		table[op].memorySize = func(ctx *opcodes.ExecutionContext) uint64 {
+			return calc.MemorySize(op, ctx)
+		}
+	}
+
+	return &table
+}
@@ -0,0 +1,77 @@
+package memory
+
+// Memory is an expandable byte buffer backing the EVM's volatile memory
+// space. It only ever grows, in whole 32-byte words, matching the EVM's
+// word-aligned expansion rule.
+type Memory struct {
+	store []byte
+}
+
+// New creates an empty Memory.
+func New() *Memory {
+	return &Memory{}
+}
+
+// Len returns the current size of memory in bytes.
+func (m *Memory) Len() int {
+	return len(m.store)
+}
+
+// Resize grows memory so it is at least 'size' bytes long, rounding up
+// to the next 32-byte word. It is a no-op if memory is already that
+// size or larger.
+func (m *Memory) Resize(size uint64) {
+	if uint64(len(m.store)) >= size {
+		return
+	}
+
+	words := (size + 31) / 32
+	newLen := words * 32
+	m.store = append(m.store, make([]byte, newLen-uint64(len(m.store)))...)
+}
+
+// Set writes 'value' into memory starting at 'offset', expanding memory
+// as needed. If value is shorter than size, the remainder is zeroed.
+func (m *Memory) Set(offset, size uint64, value []byte) {
+	if size == 0 {
+		return
+	}
+
+	m.Resize(offset + size)
+
+	n := uint64(len(value))
+	if n > size {
+		n = size
+	}
+	copy(m.store[offset:offset+n], value[:n])
+	for i := offset + n; i < offset+size; i++ {
+		m.store[i] = 0
+	}
+}
+
+// Set32 writes a single 32-byte word at 'offset', left-padding 'value'
+// with zero bytes if it is shorter than 32 bytes.
+func (m *Memory) Set32(offset uint64, value []byte) {
+	m.Resize(offset + 32)
+
+	var word [32]byte
+	if len(value) > 32 {
+		value = value[len(value)-32:]
+	}
+	copy(word[32-len(value):], value)
+	copy(m.store[offset:offset+32], word[:])
+}
+
+// Get returns a copy of the 'size' bytes of memory starting at 'offset',
+// expanding memory as needed.
+func (m *Memory) Get(offset, size uint64) []byte {
+	if size == 0 {
+		return []byte{}
+	}
+
+	m.Resize(offset + size)
+
+	cpy := make([]byte, size)
+	copy(cpy, m.store[offset:offset+size])
+	return cpy
+}
@@ -0,0 +1,65 @@
+package opcodes
+
+import "github.com/holiman/uint256"
+
+// ApplyBitwiseOp applies the EVM's bitwise logic opcodes: AND, OR, XOR,
+// NOT, BYTE, SHL, SHR, and SAR. uint256.Int already knows how to extract
+// a single byte (Byte) and perform a sign-extending right shift (SRsh),
+// so those no longer need hand-rolled masking.
+func ApplyBitwiseOp(opcode OpCode, ctx *ExecutionContext) bool {
+	if opcode == Not {
+		a := ctx.Stack.Peek()
+		a.Not(a)
+		ctx.PC++
+		return true
+	}
+
+	if ctx.Stack.Len() < 2 {
+		return false
+	}
+	x := ctx.Stack.Pop()
+	y := ctx.Stack.Peek()
+
+	switch opcode {
+	case And:
+		y.And(&x, y)
+	case Or:
+		y.Or(&x, y)
+	case Xor:
+		y.Xor(&x, y)
+	case Byte:
+		y.Byte(&x)
+	case Shl:
+		if x.LtUint64(256) {
+			y.Lsh(y, uint(x.Uint64()))
+		} else {
+			y.Clear()
+		}
+	case Shr:
+		if x.LtUint64(256) {
+			y.Rsh(y, uint(x.Uint64()))
+		} else {
+			y.Clear()
+		}
+	case Sar:
+		if x.LtUint64(256) {
+			y.SRsh(y, uint(x.Uint64()))
+		} else if isNegative(y) {
+			y.SetAllOne()
+		} else {
+			y.Clear()
+		}
+	default:
+		return false
+	}
+
+	ctx.PC++
+	return true
+}
+
+// isNegative reports whether x's high bit (bit 255) is set, i.e.
+// whether it is negative when read as a two's-complement signed value.
+func isNegative(x *uint256.Int) bool {
+	b := x.Bytes32()
+	return b[0]&0x80 != 0
+}
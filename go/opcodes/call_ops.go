@@ -0,0 +1,165 @@
+package opcodes
+
+import "github.com/holiman/uint256"
+
+// callStipendGas is the extra gas EIP-150 grants for free to a value
+// transferring call's callee, on top of whatever the caller forwards.
+const callStipendGas = 2300
+
+// applyCallFamily implements CALL, CALLCODE, DELEGATECALL, and
+// STATICCALL. It pops the opcode's arguments, asks the host to carry
+// out the call via ctx.Invoke, copies the returned data into memory,
+// and pushes a success flag. Following EIP-150, at most 63/64ths of the
+// gas remaining may be forwarded to the callee; whatever the callee
+// doesn't use - including an unused value-transfer stipend - is
+// refunded back to ctx.Gas once the call returns.
+func applyCallFamily(ctx *ExecutionContext, kind CallKind) bool {
+	hasValue := kind == CallKindCall || kind == CallKindCallCode
+	minArgs := 6
+	if hasValue {
+		minArgs = 7
+	}
+	if ctx.Stack.Len() < minArgs {
+		return false
+	}
+
+	requestedGas, _ := popLastElement(ctx)
+	addr, _ := popLastElement(ctx)
+
+	value := new(uint256.Int)
+	if hasValue {
+		v, _ := popLastElement(ctx)
+		value = &v
+	}
+
+	argsOffset, _ := popLastElement(ctx)
+	argsSize, _ := popLastElement(ctx)
+	retOffset, _ := popLastElement(ctx)
+	retSize, _ := popLastElement(ctx)
+
+	input := ctx.Memory.Get(argsOffset.Uint64(), argsSize.Uint64())
+
+	if ctx.Invoke == nil {
+		ctx.Push(new(uint256.Int))
+		ctx.PC++
+		return true
+	}
+
+	forwardable := ctx.Gas - ctx.Gas/64
+	forwarded := forwardable
+	if requestedGas.IsUint64() && requestedGas.Uint64() < forwardable {
+		forwarded = requestedGas.Uint64()
+	}
+	ctx.Gas -= forwarded
+
+	stipend := uint64(0)
+	if hasValue && !value.IsZero() {
+		stipend = callStipendGas
+	}
+
+	result := ctx.Invoke(CallRequest{
+		Kind:    kind,
+		Gas:     forwarded + stipend,
+		Address: uint256ToAddress(&addr),
+		Value:   value,
+		Input:   input,
+	})
+
+	used := result.GasUsed
+	if used > forwarded+stipend {
+		used = forwarded + stipend
+	}
+	ctx.Gas += forwarded + stipend - used
+
+	ctx.ReturnData = result.ReturnData
+	copySize := retSize.Uint64()
+	if uint64(len(result.ReturnData)) < copySize {
+		copySize = uint64(len(result.ReturnData))
+	}
+	ctx.Memory.Set(retOffset.Uint64(), copySize, result.ReturnData[:copySize])
+
+	success := new(uint256.Int)
+	if result.Success {
+		success.SetOne()
+	}
+	ctx.Push(success)
+	ctx.PC++
+	return true
+}
+
+// ApplyCall implements CALL.
+func ApplyCall(ctx *ExecutionContext) bool { return applyCallFamily(ctx, CallKindCall) }
+
+// ApplyCallcode implements CALLCODE.
+func ApplyCallcode(ctx *ExecutionContext) bool { return applyCallFamily(ctx, CallKindCallCode) }
+
+// ApplyDelegatecall implements DELEGATECALL.
+func ApplyDelegatecall(ctx *ExecutionContext) bool {
+	return applyCallFamily(ctx, CallKindDelegateCall)
+}
+
+// ApplyStaticcall implements STATICCALL.
+func ApplyStaticcall(ctx *ExecutionContext) bool { return applyCallFamily(ctx, CallKindStaticCall) }
+
+// applyCreateFamily implements CREATE and CREATE2: it forwards all but
+// 1/64th of the remaining gas to the host via ctx.Invoke, asks it to
+// deploy new code, and pushes the resulting address, or 0 on failure.
+func applyCreateFamily(ctx *ExecutionContext, kind CallKind) bool {
+	minArgs := 3
+	if kind == CallKindCreate2 {
+		minArgs = 4
+	}
+	if ctx.Stack.Len() < minArgs {
+		return false
+	}
+
+	value, _ := popLastElement(ctx)
+	offset, _ := popLastElement(ctx)
+	size, _ := popLastElement(ctx)
+
+	var salt *uint256.Int
+	if kind == CallKindCreate2 {
+		s, _ := popLastElement(ctx)
+		salt = &s
+	}
+
+	code := ctx.Memory.Get(offset.Uint64(), size.Uint64())
+
+	if ctx.Invoke == nil {
+		ctx.Push(new(uint256.Int))
+		ctx.PC++
+		return true
+	}
+
+	forwarded := ctx.Gas - ctx.Gas/64
+	ctx.Gas -= forwarded
+
+	result := ctx.Invoke(CallRequest{
+		Kind:  kind,
+		Gas:   forwarded,
+		Value: &value,
+		Code:  code,
+		Salt:  salt,
+	})
+
+	used := result.GasUsed
+	if used > forwarded {
+		used = forwarded
+	}
+	ctx.Gas += forwarded - used
+
+	ctx.ReturnData = result.ReturnData
+	if result.Success {
+		ctx.Push(addressToUint256(result.Address))
+	} else {
+		ctx.Push(new(uint256.Int))
+	}
+	ctx.PC++
+	return true
+}
+
+// ApplyCreate implements CREATE.
+func ApplyCreate(ctx *ExecutionContext) bool { return applyCreateFamily(ctx, CallKindCreate) }
+
+// ApplyCreate2 implements CREATE2.
+func ApplyCreate2(ctx *ExecutionContext) bool { return applyCreateFamily(ctx, CallKindCreate2) }
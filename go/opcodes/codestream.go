@@ -0,0 +1,59 @@
+package opcodes
+
+// CodeStream wraps a contract's bytecode together with a precomputed
+// bitmap of valid JUMPDEST positions. The bitmap is computed once, here,
+// instead of re-scanning the code on every JUMP/JUMPI, and is packed one
+// bit per code position rather than a map[int]bool for memory efficiency
+// on large contracts.
+type CodeStream struct {
+	code      []byte
+	jumpdests []byte
+}
+
+// NewCodeStream analyzes 'code' and returns a CodeStream with its
+// JUMPDEST bitmap already computed.
+func NewCodeStream(code []byte) *CodeStream {
+	cs := &CodeStream{
+		code:      code,
+		jumpdests: make([]byte, (len(code)+7)/8),
+	}
+	cs.analyze()
+	return cs
+}
+
+// analyze walks the code once, skipping over PUSH immediates so their
+// data bytes are never mistaken for a JUMPDEST opcode, and marks every
+// real JUMPDEST position in the bitmap.
+func (cs *CodeStream) analyze() {
+	for pc := 0; pc < len(cs.code); {
+		op := OpCode(cs.code[pc])
+		switch {
+		case op == Jumpdest:
+			cs.jumpdests[pc/8] |= 1 << uint(pc%8)
+			pc++
+		case op >= Push1 && op <= Push32:
+			pc += PushOpcodeToBytes[op] + 1
+		default:
+			pc++
+		}
+	}
+}
+
+// IsValidJumpDest reports whether 'dest' is within code bounds and was
+// found by analyze to be a real JUMPDEST.
+func (cs *CodeStream) IsValidJumpDest(dest int) bool {
+	if dest < 0 || dest >= len(cs.code) {
+		return false
+	}
+	return cs.jumpdests[dest/8]&(1<<uint(dest%8)) != 0
+}
+
+// Bytes returns the underlying code.
+func (cs *CodeStream) Bytes() []byte {
+	return cs.code
+}
+
+// Len returns the length of the underlying code.
+func (cs *CodeStream) Len() int {
+	return len(cs.code)
+}
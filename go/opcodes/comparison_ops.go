@@ -1,101 +1,52 @@
 package opcodes
 
-import (
-	"evm-from-scratch-go/utils"
-	"math/big"
-)
+// ApplyComparisonOp applies the EVM's comparison opcodes: LT, GT, SLT,
+// SGT, EQ, and ISZERO. Binary comparisons pop their first operand and
+// overwrite the new top of stack (their second operand) with the 0/1
+// result in place; uint256.Int already knows how to compare itself as a
+// signed two's-complement value via Slt/Sgt, so no separate sign
+// handling is needed.
+func ApplyComparisonOp(opcode OpCode, ctx *ExecutionContext) bool {
+	if opcode == Iszero {
+		a := ctx.Stack.Peek()
+		if a.IsZero() {
+			a.SetOne()
+		} else {
+			a.Clear()
+		}
+		ctx.PC++
+		return true
+	}
 
-// ApplyComparisonOp applies various comparison operations based on the provided opcode.
-// Supported operations include lt, gt, slt, and sgt.
-func ApplyComparisonOp(opcode OpCode, pc *int, stack *[]*big.Int) bool {
-	if len(*stack) < 2 {
+	if ctx.Stack.Len() < 2 {
 		return false
 	}
 
-	// Pop the last two elements from the stack.
-	a, _ := popLastElement(pc, stack)
-	b, _ := popLastElement(pc, stack)
+	x := ctx.Stack.Pop()
+	y := ctx.Stack.Peek()
 
-	var result *big.Int
+	var result bool
 	switch opcode {
 	case Lt:
-		result = ltComparison(a, b)
+		result = x.Lt(y)
 	case Gt:
-		result = gtComparison(a, b)
+		result = x.Gt(y)
 	case Slt:
-		result = sltComparison(a, b)
+		result = x.Slt(y)
 	case Sgt:
-		result = sgtComparison(a, b)
+		result = x.Sgt(y)
+	case Eq:
+		result = x.Eq(y)
 	default:
 		return false
 	}
 
-	*stack = append(*stack, result)
-	*pc++
-
-	return true
-}
-
-// ltComparison returns 1 if a < b, 0 otherwise.
-func ltComparison(a, b *big.Int) *big.Int {
-	if a.Cmp(b) < 0 {
-		return big.NewInt(1)
+	if result {
+		y.SetOne()
+	} else {
+		y.Clear()
 	}
-	return big.NewInt(0)
-}
 
-// gtComparison returns 1 if a > b, 0 otherwise.
-func gtComparison(a, b *big.Int) *big.Int {
-	if a.Cmp(b) > 0 {
-		return big.NewInt(1)
-	}
-	return big.NewInt(0)
-}
-
-// sltComparison returns 1 if a < b, 0 otherwise.
-// It compares the two values as signed integers.
-// If both a and b are negative, it compares their absolute values.
-func sltComparison(a, b *big.Int) *big.Int {
-	switch {
-	case utils.IsNegative(a) && !utils.IsNegative(b):
-		return big.NewInt(1)
-	case !utils.IsNegative(a) && utils.IsNegative(b):
-		return big.NewInt(0)
-	case utils.IsNegative(a) && utils.IsNegative(b):
-		aNeg := utils.OverflowingNeg(a)
-		bNeg := utils.OverflowingNeg(b)
-		if aNeg.Cmp(bNeg) <= 0 {
-			return big.NewInt(0)
-		}
-		return big.NewInt(1)
-	default:
-		if a.Cmp(b) < 0 {
-			return big.NewInt(1)
-		}
-		return big.NewInt(0)
-	}
-}
-
-// sgtComparison returns 1 if a > b, 0 otherwise.
-// It compares the two values as signed integers.
-// If both a and b are negative, it compares their absolute values.
-func sgtComparison(a, b *big.Int) *big.Int {
-	switch {
-	case utils.IsNegative(a) && !utils.IsNegative(b):
-		return big.NewInt(0)
-	case !utils.IsNegative(a) && utils.IsNegative(b):
-		return big.NewInt(1)
-	case utils.IsNegative(a) && utils.IsNegative(b):
-		aNeg := utils.OverflowingNeg(a)
-		bNeg := utils.OverflowingNeg(b)
-		if aNeg.Cmp(bNeg) >= 0 {
-			return big.NewInt(0)
-		}
-		return big.NewInt(1)
-	default:
-		if a.Cmp(b) > 0 {
-			return big.NewInt(1)
-		}
-		return big.NewInt(0)
-	}
+	ctx.PC++
+	return true
 }
@@ -0,0 +1,234 @@
+package opcodes
+
+import (
+	"github.com/holiman/uint256"
+
+	"evm-from-scratch-go/memory"
+	"evm-from-scratch-go/stack"
+	"evm-from-scratch-go/state"
+)
+
+// CallContext carries the parameters of the current call frame: the
+// executing contract's address, who is calling it, how much value was
+// attached, and the calldata it was invoked with.
+type CallContext struct {
+	Address  [20]byte
+	Caller   [20]byte
+	Value    *uint256.Int
+	CallData []byte
+}
+
+// BlockContext carries block-level environment values that stay
+// constant for the whole transaction (COINBASE, TIMESTAMP, NUMBER, ...).
+type BlockContext struct {
+	Coinbase    [20]byte
+	GasLimit    uint64
+	BlockNumber *uint256.Int
+	Time        uint64
+	Difficulty  *uint256.Int
+	BaseFee     *uint256.Int
+	ChainID     *uint256.Int
+
+	// GetHash returns the hash of the block at the given number, for
+	// BLOCKHASH. It may be nil, in which case BLOCKHASH always yields 0.
+	GetHash func(blockNumber uint64) [32]byte
+}
+
+// TxContext carries transaction-level environment values (ORIGIN,
+// GASPRICE).
+type TxContext struct {
+	Origin   [20]byte
+	GasPrice *uint256.Int
+}
+
+// Log is a single entry emitted by LOG0-LOG4.
+type Log struct {
+	Address [20]byte
+	Topics  [][32]byte
+	Data    []byte
+}
+
+// CallKind identifies which call-family or create-family opcode
+// produced a CallRequest.
+type CallKind int
+
+const (
+	CallKindCall CallKind = iota
+	CallKindCallCode
+	CallKindDelegateCall
+	CallKindStaticCall
+	CallKindCreate
+	CallKindCreate2
+)
+
+// CallRequest describes an outgoing call or contract creation that the
+// host, through ExecutionContext.Invoke, is asked to carry out.
+type CallRequest struct {
+	Kind    CallKind
+	Gas     uint64
+	Address [20]byte
+	Value   *uint256.Int
+	Input   []byte
+	Code    []byte       // CREATE/CREATE2 only: the init code to run
+	Salt    *uint256.Int // CREATE2 only
+}
+
+// CallResult is what the host reports back after carrying out a
+// CallRequest.
+type CallResult struct {
+	Success    bool
+	ReturnData []byte
+	Address    [20]byte // the deployed address, for CREATE/CREATE2
+	GasUsed    uint64   // gas consumed out of the CallRequest's forwarded Gas
+}
+
+// slotKey addresses a single storage slot of a single account, for the
+// per-execution bookkeeping EIP-2929 access lists and EIP-2200 net gas
+// metering need.
+type slotKey struct {
+	addr [20]byte
+	key  [32]byte
+}
+
+// ExecutionContext bundles everything a single opcode needs to run: the
+// program counter, the stack, volatile memory, the executing contract's
+// code and call frame, the surrounding block/tx environment, and a
+// handle to world state. It replaces threading the program counter and
+// stack through every opcode function individually.
+type ExecutionContext struct {
+	PC     int
+	Stack  *stack.Stack
+	Memory *memory.Memory
+	Code   []byte
+	Jumps  *CodeStream
+	Gas    uint64
+
+	Call  CallContext
+	Block BlockContext
+	Tx    TxContext
+	State state.StateDB
+
+	// Invoke carries out CALL/CREATE-family requests by recursing into
+	// the host's interpreter loop. It is nil when no such host is
+	// wired up, in which case the call-family opcodes report failure.
+	Invoke func(CallRequest) CallResult
+
+	ReturnData []byte // return data of the most recently completed sub-call
+	Returned   []byte // data handed to RETURN/REVERT by this frame
+	Logs       []Log
+
+	Halted   bool
+	Reverted bool
+
+	SelfDestructed          bool
+	SelfDestructBeneficiary [20]byte
+
+	refund          uint64
+	warmAddresses   map[[20]byte]struct{}
+	warmSlots       map[slotKey]struct{}
+	originalStorage map[slotKey][32]byte
+}
+
+// NewExecutionContext creates an ExecutionContext ready to run 'code'
+// from the beginning: PC at 0, an empty stack, and empty memory. The
+// executing contract's own address and the transaction's origin start
+// out warm, per EIP-2929.
+func NewExecutionContext(code []byte, call CallContext, block BlockContext, tx TxContext, db state.StateDB) *ExecutionContext {
+	ctx := &ExecutionContext{
+		Stack:  stack.New(),
+		Memory: memory.New(),
+		Code:   code,
+		Jumps:  NewCodeStream(code),
+		Call:   call,
+		Block:  block,
+		Tx:     tx,
+		State:  db,
+	}
+	ctx.AccessAddress(call.Address)
+	ctx.AccessAddress(tx.Origin)
+	return ctx
+}
+
+// AccessAddress records addr as accessed for EIP-2929 purposes and
+// reports whether it was already warm.
+func (ctx *ExecutionContext) AccessAddress(addr [20]byte) (warm bool) {
+	if ctx.warmAddresses == nil {
+		ctx.warmAddresses = make(map[[20]byte]struct{})
+	}
+	_, warm = ctx.warmAddresses[addr]
+	ctx.warmAddresses[addr] = struct{}{}
+	return warm
+}
+
+// AccessSlot records the storage slot (addr, key) as accessed for
+// EIP-2929 purposes and reports whether it was already warm.
+func (ctx *ExecutionContext) AccessSlot(addr [20]byte, key [32]byte) (warm bool) {
+	if ctx.warmSlots == nil {
+		ctx.warmSlots = make(map[slotKey]struct{})
+	}
+	k := slotKey{addr, key}
+	_, warm = ctx.warmSlots[k]
+	ctx.warmSlots[k] = struct{}{}
+	return warm
+}
+
+// OriginalStorage returns the value of storage slot (addr, key) as of
+// the start of this execution - the reference point EIP-2200's net gas
+// metering measures an SSTORE's cost against. The first read for a
+// given slot snapshots State's current value; later calls return that
+// same snapshot even if State has since been written.
+func (ctx *ExecutionContext) OriginalStorage(addr [20]byte, key [32]byte) [32]byte {
+	if ctx.originalStorage == nil {
+		ctx.originalStorage = make(map[slotKey][32]byte)
+	}
+	k := slotKey{addr, key}
+	if v, ok := ctx.originalStorage[k]; ok {
+		return v
+	}
+	v := ctx.State.GetState(addr, key)
+	ctx.originalStorage[k] = v
+	return v
+}
+
+// Refund returns the accumulated gas refund counter.
+func (ctx *ExecutionContext) Refund() uint64 {
+	return ctx.refund
+}
+
+// AddRefund adds amount to the gas refund counter.
+func (ctx *ExecutionContext) AddRefund(amount uint64) {
+	ctx.refund += amount
+}
+
+// SubRefund removes amount from the gas refund counter. It panics if
+// amount exceeds the counter, which would indicate a bug in the gas
+// rules crediting and debiting it.
+func (ctx *ExecutionContext) SubRefund(amount uint64) {
+	if amount > ctx.refund {
+		panic("evm: refund counter below zero")
+	}
+	ctx.refund -= amount
+}
+
+// Push pushes a copy of v onto the stack.
+func (ctx *ExecutionContext) Push(v *uint256.Int) {
+	ctx.Stack.Push(v)
+}
+
+// Pop pops and returns the top value of the stack. ok is false if the
+// stack was empty.
+func (ctx *ExecutionContext) Pop() (uint256.Int, bool) {
+	if ctx.Stack.Len() == 0 {
+		return uint256.Int{}, false
+	}
+	return ctx.Stack.Pop(), true
+}
+
+// Peek returns the n-th element from the top of the stack (0 is the
+// top) without removing it.
+func (ctx *ExecutionContext) Peek(n int) (*uint256.Int, bool) {
+	if ctx.Stack.Len() <= n {
+		return nil, false
+	}
+	return ctx.Stack.PeekAt(n), true
+}
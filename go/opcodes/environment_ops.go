@@ -0,0 +1,315 @@
+package opcodes
+
+import "github.com/holiman/uint256"
+
+// addressToUint256 renders a 20-byte address as an integer.
+func addressToUint256(addr [20]byte) *uint256.Int {
+	var x uint256.Int
+	x.SetBytes(addr[:])
+	return &x
+}
+
+// AddressFromWord truncates x to its low 20 bytes, the rule the EVM
+// uses whenever a stack value names an address. It is exported for the
+// gas package, which needs it to resolve EIP-2929 access-list costs.
+func AddressFromWord(x *uint256.Int) [20]byte {
+	return uint256ToAddress(x)
+}
+
+// uint256ToAddress truncates x to its low 20 bytes, matching how the
+// EVM interprets a stack value as an address.
+func uint256ToAddress(x *uint256.Int) [20]byte {
+	var addr [20]byte
+	b := x.Bytes()
+	if len(b) > 20 {
+		b = b[len(b)-20:]
+	}
+	copy(addr[20-len(b):], b)
+	return addr
+}
+
+// readPadded reads 'size' bytes from data starting at 'offset',
+// treating any positions past the end of data as zero. offset arrives
+// as a *uint256.Int since it comes straight off the stack.
+func readPadded(data []byte, offset *uint256.Int, size uint64) []byte {
+	out := make([]byte, size)
+	if !offset.IsUint64() {
+		return out
+	}
+
+	off := offset.Uint64()
+	if off >= uint64(len(data)) {
+		return out
+	}
+
+	n := uint64(len(data)) - off
+	if n > size {
+		n = size
+	}
+	copy(out, data[off:off+n])
+	return out
+}
+
+// ApplyAddress implements ADDRESS: push the address of the currently
+// executing contract.
+func ApplyAddress(ctx *ExecutionContext) bool {
+	ctx.Push(addressToUint256(ctx.Call.Address))
+	ctx.PC++
+	return true
+}
+
+// ApplyBalance implements BALANCE: pop an address and push its balance.
+func ApplyBalance(ctx *ExecutionContext) bool {
+	addr, ok := popLastElement(ctx)
+	if !ok {
+		return false
+	}
+	ctx.Push(ctx.State.GetBalance(uint256ToAddress(&addr)))
+	ctx.PC++
+	return true
+}
+
+// ApplyOrigin implements ORIGIN: push the address that originated the
+// transaction.
+func ApplyOrigin(ctx *ExecutionContext) bool {
+	ctx.Push(addressToUint256(ctx.Tx.Origin))
+	ctx.PC++
+	return true
+}
+
+// ApplyCaller implements CALLER: push the address that invoked the
+// current call.
+func ApplyCaller(ctx *ExecutionContext) bool {
+	ctx.Push(addressToUint256(ctx.Call.Caller))
+	ctx.PC++
+	return true
+}
+
+// ApplyCallvalue implements CALLVALUE: push the value, in wei, attached
+// to the current call.
+func ApplyCallvalue(ctx *ExecutionContext) bool {
+	ctx.Push(new(uint256.Int).Set(ctx.Call.Value))
+	ctx.PC++
+	return true
+}
+
+// ApplyCalldataload implements CALLDATALOAD: pop an offset and push the
+// 32-byte word of calldata starting there, zero-padded past the end.
+func ApplyCalldataload(ctx *ExecutionContext) bool {
+	offset, ok := popLastElement(ctx)
+	if !ok {
+		return false
+	}
+	var result uint256.Int
+	result.SetBytes(readPadded(ctx.Call.CallData, &offset, 32))
+	ctx.Push(&result)
+	ctx.PC++
+	return true
+}
+
+// ApplyCalldatasize implements CALLDATASIZE: push the length of the
+// calldata.
+func ApplyCalldatasize(ctx *ExecutionContext) bool {
+	ctx.Push(new(uint256.Int).SetUint64(uint64(len(ctx.Call.CallData))))
+	ctx.PC++
+	return true
+}
+
+// ApplyCalldatacopy implements CALLDATACOPY: copy a span of calldata
+// into memory.
+func ApplyCalldatacopy(ctx *ExecutionContext) bool {
+	if ctx.Stack.Len() < 3 {
+		return false
+	}
+	destOffset, _ := popLastElement(ctx)
+	offset, _ := popLastElement(ctx)
+	size, _ := popLastElement(ctx)
+
+	ctx.Memory.Set(destOffset.Uint64(), size.Uint64(), readPadded(ctx.Call.CallData, &offset, size.Uint64()))
+	ctx.PC++
+	return true
+}
+
+// ApplyCodesize implements CODESIZE: push the length of the currently
+// executing contract's own code.
+func ApplyCodesize(ctx *ExecutionContext) bool {
+	ctx.Push(new(uint256.Int).SetUint64(uint64(len(ctx.Code))))
+	ctx.PC++
+	return true
+}
+
+// ApplyCodecopy implements CODECOPY: copy a span of the currently
+// executing contract's own code into memory.
+func ApplyCodecopy(ctx *ExecutionContext) bool {
+	if ctx.Stack.Len() < 3 {
+		return false
+	}
+	destOffset, _ := popLastElement(ctx)
+	offset, _ := popLastElement(ctx)
+	size, _ := popLastElement(ctx)
+
+	ctx.Memory.Set(destOffset.Uint64(), size.Uint64(), readPadded(ctx.Code, &offset, size.Uint64()))
+	ctx.PC++
+	return true
+}
+
+// ApplyGasprice implements GASPRICE: push the gas price of the current
+// transaction.
+func ApplyGasprice(ctx *ExecutionContext) bool {
+	ctx.Push(new(uint256.Int).Set(ctx.Tx.GasPrice))
+	ctx.PC++
+	return true
+}
+
+// ApplyExtcodesize implements EXTCODESIZE: pop an address and push the
+// length of its code.
+func ApplyExtcodesize(ctx *ExecutionContext) bool {
+	addr, ok := popLastElement(ctx)
+	if !ok {
+		return false
+	}
+	ctx.Push(new(uint256.Int).SetUint64(uint64(ctx.State.GetCodeSize(uint256ToAddress(&addr)))))
+	ctx.PC++
+	return true
+}
+
+// ApplyExtcodecopy implements EXTCODECOPY: copy a span of another
+// account's code into memory.
+func ApplyExtcodecopy(ctx *ExecutionContext) bool {
+	if ctx.Stack.Len() < 4 {
+		return false
+	}
+	addr, _ := popLastElement(ctx)
+	destOffset, _ := popLastElement(ctx)
+	offset, _ := popLastElement(ctx)
+	size, _ := popLastElement(ctx)
+
+	code := ctx.State.GetCode(uint256ToAddress(&addr))
+	ctx.Memory.Set(destOffset.Uint64(), size.Uint64(), readPadded(code, &offset, size.Uint64()))
+	ctx.PC++
+	return true
+}
+
+// ApplyExtcodehash implements EXTCODEHASH: pop an address and push the
+// keccak256 hash of its code.
+func ApplyExtcodehash(ctx *ExecutionContext) bool {
+	addr, ok := popLastElement(ctx)
+	if !ok {
+		return false
+	}
+	hash := ctx.State.GetCodeHash(uint256ToAddress(&addr))
+	var result uint256.Int
+	result.SetBytes(hash[:])
+	ctx.Push(&result)
+	ctx.PC++
+	return true
+}
+
+// ApplyReturndatasize implements RETURNDATASIZE: push the length of the
+// return data from the most recently completed sub-call.
+func ApplyReturndatasize(ctx *ExecutionContext) bool {
+	ctx.Push(new(uint256.Int).SetUint64(uint64(len(ctx.ReturnData))))
+	ctx.PC++
+	return true
+}
+
+// ApplyReturndatacopy implements RETURNDATACOPY: copy a span of the
+// return data from the most recently completed sub-call into memory.
+func ApplyReturndatacopy(ctx *ExecutionContext) bool {
+	if ctx.Stack.Len() < 3 {
+		return false
+	}
+	destOffset, _ := popLastElement(ctx)
+	offset, _ := popLastElement(ctx)
+	size, _ := popLastElement(ctx)
+
+	if !offset.IsUint64() || !size.IsUint64() || offset.Uint64()+size.Uint64() > uint64(len(ctx.ReturnData)) {
+		return false
+	}
+
+	off := offset.Uint64()
+	ctx.Memory.Set(destOffset.Uint64(), size.Uint64(), ctx.ReturnData[off:off+size.Uint64()])
+	ctx.PC++
+	return true
+}
+
+// ApplyBlockhash implements BLOCKHASH: pop a block number and push the
+// hash of that block (0 if it is unavailable).
+func ApplyBlockhash(ctx *ExecutionContext) bool {
+	number, ok := popLastElement(ctx)
+	if !ok {
+		return false
+	}
+	if ctx.Block.GetHash == nil || !number.IsUint64() {
+		ctx.Push(new(uint256.Int))
+		ctx.PC++
+		return true
+	}
+	hash := ctx.Block.GetHash(number.Uint64())
+	var result uint256.Int
+	result.SetBytes(hash[:])
+	ctx.Push(&result)
+	ctx.PC++
+	return true
+}
+
+// ApplyCoinbase implements COINBASE: push the current block's miner
+// address.
+func ApplyCoinbase(ctx *ExecutionContext) bool {
+	ctx.Push(addressToUint256(ctx.Block.Coinbase))
+	ctx.PC++
+	return true
+}
+
+// ApplyTimestamp implements TIMESTAMP: push the current block's
+// timestamp.
+func ApplyTimestamp(ctx *ExecutionContext) bool {
+	ctx.Push(new(uint256.Int).SetUint64(ctx.Block.Time))
+	ctx.PC++
+	return true
+}
+
+// ApplyNumber implements NUMBER: push the current block's number.
+func ApplyNumber(ctx *ExecutionContext) bool {
+	ctx.Push(new(uint256.Int).Set(ctx.Block.BlockNumber))
+	ctx.PC++
+	return true
+}
+
+// ApplyDifficulty implements DIFFICULTY (PREVRANDAO post-Merge): push
+// the current block's difficulty/randomness value.
+func ApplyDifficulty(ctx *ExecutionContext) bool {
+	ctx.Push(new(uint256.Int).Set(ctx.Block.Difficulty))
+	ctx.PC++
+	return true
+}
+
+// ApplyGaslimit implements GASLIMIT: push the current block's gas
+// limit.
+func ApplyGaslimit(ctx *ExecutionContext) bool {
+	ctx.Push(new(uint256.Int).SetUint64(ctx.Block.GasLimit))
+	ctx.PC++
+	return true
+}
+
+// ApplyChainid implements CHAINID: push the configured chain ID.
+func ApplyChainid(ctx *ExecutionContext) bool {
+	ctx.Push(new(uint256.Int).Set(ctx.Block.ChainID))
+	ctx.PC++
+	return true
+}
+
+// ApplySelfbalance implements SELFBALANCE: push the balance of the
+// currently executing contract.
+func ApplySelfbalance(ctx *ExecutionContext) bool {
+	ctx.Push(ctx.State.GetBalance(ctx.Call.Address))
+	ctx.PC++
+	return true
+}
+
+// ApplyBasefee implements BASEFEE: push the current block's base fee.
+func ApplyBasefee(ctx *ExecutionContext) bool {
+	ctx.Push(new(uint256.Int).Set(ctx.Block.BaseFee))
+	ctx.PC++
+	return true
+}
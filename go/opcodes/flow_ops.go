@@ -0,0 +1,98 @@
+package opcodes
+
+import "github.com/holiman/uint256"
+
+// ApplyJump implements JUMP: pop a destination and set PC to it. The
+// destination must land on a JUMPDEST, per ctx.Jumps' precomputed
+// bitmap.
+func ApplyJump(ctx *ExecutionContext) bool {
+	dest, ok := popLastElement(ctx)
+	if !ok {
+		return false
+	}
+	if !dest.IsUint64() || !ctx.Jumps.IsValidJumpDest(int(dest.Uint64())) {
+		return false
+	}
+	ctx.PC = int(dest.Uint64())
+	return true
+}
+
+// ApplyJumpi implements JUMPI: pop a destination and a condition, and
+// jump only if the condition is non-zero.
+func ApplyJumpi(ctx *ExecutionContext) bool {
+	if ctx.Stack.Len() < 2 {
+		return false
+	}
+	dest, _ := popLastElement(ctx)
+	cond, _ := popLastElement(ctx)
+
+	if cond.IsZero() {
+		ctx.PC++
+		return true
+	}
+
+	if !dest.IsUint64() || !ctx.Jumps.IsValidJumpDest(int(dest.Uint64())) {
+		return false
+	}
+	ctx.PC = int(dest.Uint64())
+	return true
+}
+
+// ApplyJumpdest implements JUMPDEST: a no-op marker for valid jump
+// targets.
+func ApplyJumpdest(ctx *ExecutionContext) bool {
+	ctx.PC++
+	return true
+}
+
+// ApplyPc implements PC: push the program counter of this instruction.
+func ApplyPc(ctx *ExecutionContext) bool {
+	var result uint256.Int
+	result.SetUint64(uint64(ctx.PC))
+	ctx.Push(&result)
+	ctx.PC++
+	return true
+}
+
+// ApplyReturn implements RETURN: pop an offset and size describing the
+// span of memory to return, and halt successfully.
+func ApplyReturn(ctx *ExecutionContext) bool {
+	if ctx.Stack.Len() < 2 {
+		return false
+	}
+	offset, _ := popLastElement(ctx)
+	size, _ := popLastElement(ctx)
+
+	ctx.Returned = ctx.Memory.Get(offset.Uint64(), size.Uint64())
+	ctx.Halted = true
+	return true
+}
+
+// ApplyRevert implements REVERT: like RETURN, but marks execution as
+// reverted so the caller rolls back any state changes from this frame.
+func ApplyRevert(ctx *ExecutionContext) bool {
+	if ctx.Stack.Len() < 2 {
+		return false
+	}
+	offset, _ := popLastElement(ctx)
+	size, _ := popLastElement(ctx)
+
+	ctx.Returned = ctx.Memory.Get(offset.Uint64(), size.Uint64())
+	ctx.Reverted = true
+	ctx.Halted = true
+	return true
+}
+
+// ApplySelfdestruct implements SELFDESTRUCT: pop a beneficiary address,
+// record it and mark the executing contract as self-destructed on ctx,
+// and halt. No balance is moved and no account is removed here.
+func ApplySelfdestruct(ctx *ExecutionContext) bool {
+	beneficiary, ok := popLastElement(ctx)
+	if !ok {
+		return false
+	}
+	ctx.SelfDestructed = true
+	ctx.SelfDestructBeneficiary = uint256ToAddress(&beneficiary)
+	ctx.Halted = true
+	return true
+}
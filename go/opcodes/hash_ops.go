@@ -0,0 +1,28 @@
+package opcodes
+
+import (
+	"github.com/holiman/uint256"
+	"golang.org/x/crypto/sha3"
+)
+
+// ApplySha3 implements SHA3 (KECCAK256): it pops an offset and a size
+// from the stack, hashes that span of memory with Keccak-256, and
+// pushes the resulting 32-byte digest.
+func ApplySha3(ctx *ExecutionContext) bool {
+	if ctx.Stack.Len() < 2 {
+		return false
+	}
+	offset, _ := popLastElement(ctx)
+	size, _ := popLastElement(ctx)
+
+	data := ctx.Memory.Get(offset.Uint64(), size.Uint64())
+
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(data)
+
+	var result uint256.Int
+	result.SetBytes(hash.Sum(nil))
+	ctx.Push(&result)
+	ctx.PC++
+	return true
+}
@@ -0,0 +1,30 @@
+package opcodes
+
+// ApplyLog implements LOG0..LOG4: pop an offset and size describing a
+// span of memory to emit as log data, then pop 'topics' additional
+// 32-byte topics, and record the resulting log entry on the execution
+// context.
+func ApplyLog(ctx *ExecutionContext, topics int) bool {
+	if ctx.Stack.Len() < 2+topics {
+		return false
+	}
+	offset, _ := popLastElement(ctx)
+	size, _ := popLastElement(ctx)
+
+	data := ctx.Memory.Get(offset.Uint64(), size.Uint64())
+
+	logTopics := make([][32]byte, topics)
+	for i := 0; i < topics; i++ {
+		t, _ := popLastElement(ctx)
+		logTopics[i] = uint256ToHash(&t)
+	}
+
+	ctx.Logs = append(ctx.Logs, Log{
+		Address: ctx.Call.Address,
+		Topics:  logTopics,
+		Data:    data,
+	})
+
+	ctx.PC++
+	return true
+}
@@ -0,0 +1,56 @@
+package opcodes
+
+import "github.com/holiman/uint256"
+
+// ApplyMload implements MLOAD: pop an offset and push the 32-byte word
+// stored there, expanding memory as needed.
+func ApplyMload(ctx *ExecutionContext) bool {
+	offset, ok := popLastElement(ctx)
+	if !ok {
+		return false
+	}
+	word := ctx.Memory.Get(offset.Uint64(), 32)
+	var result uint256.Int
+	result.SetBytes(word)
+	ctx.Push(&result)
+	ctx.PC++
+	return true
+}
+
+// ApplyMstore implements MSTORE: pop an offset and a value, and write
+// the value as a 32-byte word at that offset.
+func ApplyMstore(ctx *ExecutionContext) bool {
+	if ctx.Stack.Len() < 2 {
+		return false
+	}
+	offset, _ := popLastElement(ctx)
+	value, _ := popLastElement(ctx)
+
+	ctx.Memory.Set32(offset.Uint64(), value.Bytes())
+	ctx.PC++
+	return true
+}
+
+// ApplyMstore8 implements MSTORE8: pop an offset and a value, and write
+// the least-significant byte of the value at that offset.
+func ApplyMstore8(ctx *ExecutionContext) bool {
+	if ctx.Stack.Len() < 2 {
+		return false
+	}
+	offset, _ := popLastElement(ctx)
+	value, _ := popLastElement(ctx)
+
+	ctx.Memory.Set(offset.Uint64(), 1, []byte{byte(value.Uint64())})
+	ctx.PC++
+	return true
+}
+
+// ApplyMsize implements MSIZE: push the current size of memory in
+// bytes.
+func ApplyMsize(ctx *ExecutionContext) bool {
+	var result uint256.Int
+	result.SetUint64(uint64(ctx.Memory.Len()))
+	ctx.Push(&result)
+	ctx.PC++
+	return true
+}
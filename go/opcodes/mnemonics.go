@@ -0,0 +1,58 @@
+package opcodes
+
+import "fmt"
+
+// Mnemonics maps every opcode this interpreter recognizes to its
+// standard assembly name, for disassembly and tooling. Entries for the
+// repetitive PUSH/DUP/SWAP/LOG ranges are filled in below rather than
+// spelled out individually.
+var Mnemonics = buildMnemonics()
+
+func buildMnemonics() map[OpCode]string {
+	m := map[OpCode]string{
+		Stop: "STOP", Add: "ADD", Mul: "MUL", Sub: "SUB", Div: "DIV",
+		Sdiv: "SDIV", Mod: "MOD", Smod: "SMOD", Addmod: "ADDMOD",
+		Mulmod: "MULMOD", Exp: "EXP", Signextend: "SIGNEXTEND",
+
+		Lt: "LT", Gt: "GT", Slt: "SLT", Sgt: "SGT", Eq: "EQ",
+		Iszero: "ISZERO", And: "AND", Or: "OR", Xor: "XOR", Not: "NOT",
+		Byte: "BYTE", Shl: "SHL", Shr: "SHR", Sar: "SAR",
+
+		Sha3: "SHA3",
+
+		Address: "ADDRESS", Balance: "BALANCE", Origin: "ORIGIN",
+		Caller: "CALLER", Callvalue: "CALLVALUE", Calldataload: "CALLDATALOAD",
+		Calldatasize: "CALLDATASIZE", Calldatacopy: "CALLDATACOPY",
+		Codesize: "CODESIZE", Codecopy: "CODECOPY", Gasprice: "GASPRICE",
+		Extcodesize: "EXTCODESIZE", Extcodecopy: "EXTCODECOPY",
+		Returndatasize: "RETURNDATASIZE", Returndatacopy: "RETURNDATACOPY",
+		Extcodehash: "EXTCODEHASH", Blockhash: "BLOCKHASH", Coinbase: "COINBASE",
+		Timestamp: "TIMESTAMP", Number: "NUMBER", Difficulty: "DIFFICULTY",
+		Gaslimit: "GASLIMIT", Chainid: "CHAINID", Selfbalance: "SELFBALANCE",
+		Basefee: "BASEFEE",
+
+		Pop: "POP", Mload: "MLOAD", Mstore: "MSTORE", Mstore8: "MSTORE8",
+		Sload: "SLOAD", Sstore: "SSTORE", Jump: "JUMP", Jumpi: "JUMPI",
+		Pc: "PC", Msize: "MSIZE", Gas: "GAS", Jumpdest: "JUMPDEST",
+		Tload: "TLOAD", Tstore: "TSTORE",
+
+		Push0: "PUSH0",
+
+		Create: "CREATE", Call: "CALL", Callcode: "CALLCODE", Return: "RETURN",
+		Delegatecall: "DELEGATECALL", Create2: "CREATE2", Staticcall: "STATICCALL",
+		Revert: "REVERT", Invalid: "INVALID", Selfdestruct: "SELFDESTRUCT",
+	}
+
+	for i := 0; i < 32; i++ {
+		m[Push1+OpCode(i)] = fmt.Sprintf("PUSH%d", i+1)
+	}
+	for i := 0; i < 16; i++ {
+		m[Dup1+OpCode(i)] = fmt.Sprintf("DUP%d", i+1)
+		m[Swap1+OpCode(i)] = fmt.Sprintf("SWAP%d", i+1)
+	}
+	for i := 0; i < 5; i++ {
+		m[Log0+OpCode(i)] = fmt.Sprintf("LOG%d", i)
+	}
+
+	return m
+}
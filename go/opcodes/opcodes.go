@@ -4,83 +4,235 @@ import (
 	"errors"
 )
 
-// OpCode represents the operation codes (opcodes) in the EVM.
+// OpCode represents the operation codes (opcodes) in the EVM. Its
+// numeric value is identical to the opcode's single-byte encoding in
+// EVM bytecode, so converting a raw code byte to an OpCode is a direct
+// cast.
 type OpCode byte
 
-// Define opcodes as constants.
+// Stop and arithmetic opcodes (0x00-0x0b).
 const (
-	Stop OpCode = iota
-	Push0
-	Push1
-	Push2
-	Push4
-	Push6
-	Push10
-	Push11
-	Push32
-	Pop
-	Add
-	Mul
-	Sub
-	Div
-	Sdiv
-	Mod
-	Smod
-	Addmod
-	Mulmod
-	Exp
-	Signextend
-	Lt
-	Gt
-	Slt
-	Sgt
+	Stop OpCode = 0x00
+	Add  OpCode = 0x01
+	Mul  OpCode = 0x02
+	Sub  OpCode = 0x03
+	Div  OpCode = 0x04
+	Sdiv OpCode = 0x05
+	Mod  OpCode = 0x06
+	Smod OpCode = 0x07
+
+	Addmod     OpCode = 0x08
+	Mulmod     OpCode = 0x09
+	Exp        OpCode = 0x0a
+	Signextend OpCode = 0x0b
 )
 
-// opCodeMap maps byte values to OpCode.
-var opCodeMap = map[byte]OpCode{
-	0:   Stop,
-	95:  Push0,
-	96:  Push1,
-	97:  Push2,
-	99:  Push4,
-	101: Push6,
-	105: Push10,
-	106: Push11,
-	127: Push32,
-	80:  Pop,
-	1:   Add,
-	2:   Mul,
-	3:   Sub,
-	4:   Div,
-	5:   Sdiv,
-	6:   Mod,
-	7:   Smod,
-	8:   Addmod,
-	9:   Mulmod,
-	10:  Exp,
-	11:  Signextend,
-	16:  Lt,
-	17:  Gt,
-	18:  Slt,
-	19:  Sgt,
-}
+// Comparison & bitwise logic opcodes (0x10-0x1d).
+const (
+	Lt     OpCode = 0x10
+	Gt     OpCode = 0x11
+	Slt    OpCode = 0x12
+	Sgt    OpCode = 0x13
+	Eq     OpCode = 0x14
+	Iszero OpCode = 0x15
+	And    OpCode = 0x16
+	Or     OpCode = 0x17
+	Xor    OpCode = 0x18
+	Not    OpCode = 0x19
+	Byte   OpCode = 0x1a
+	Shl    OpCode = 0x1b
+	Shr    OpCode = 0x1c
+	Sar    OpCode = 0x1d
+)
+
+// Hashing (0x20).
+const (
+	Sha3 OpCode = 0x20
+)
+
+// Environmental information (0x30-0x48).
+const (
+	Address        OpCode = 0x30
+	Balance        OpCode = 0x31
+	Origin         OpCode = 0x32
+	Caller         OpCode = 0x33
+	Callvalue      OpCode = 0x34
+	Calldataload   OpCode = 0x35
+	Calldatasize   OpCode = 0x36
+	Calldatacopy   OpCode = 0x37
+	Codesize       OpCode = 0x38
+	Codecopy       OpCode = 0x39
+	Gasprice       OpCode = 0x3a
+	Extcodesize    OpCode = 0x3b
+	Extcodecopy    OpCode = 0x3c
+	Returndatasize OpCode = 0x3d
+	Returndatacopy OpCode = 0x3e
+	Extcodehash    OpCode = 0x3f
+
+	Blockhash   OpCode = 0x40
+	Coinbase    OpCode = 0x41
+	Timestamp   OpCode = 0x42
+	Number      OpCode = 0x43
+	Difficulty  OpCode = 0x44 // aka PREVRANDAO post-Merge
+	Gaslimit    OpCode = 0x45
+	Chainid     OpCode = 0x46
+	Selfbalance OpCode = 0x47
+	Basefee     OpCode = 0x48
+)
+
+// Stack, memory, storage, and flow opcodes (0x50-0x5d).
+const (
+	Pop      OpCode = 0x50
+	Mload    OpCode = 0x51
+	Mstore   OpCode = 0x52
+	Mstore8  OpCode = 0x53
+	Sload    OpCode = 0x54
+	Sstore   OpCode = 0x55
+	Jump     OpCode = 0x56
+	Jumpi    OpCode = 0x57
+	Pc       OpCode = 0x58
+	Msize    OpCode = 0x59
+	Gas      OpCode = 0x5a
+	Jumpdest OpCode = 0x5b
+	Tload    OpCode = 0x5c // EIP-1153 transient storage
+	Tstore   OpCode = 0x5d
+)
+
+// Push opcodes (0x5f-0x7f).
+const (
+	Push0 OpCode = 0x5f
+	Push1 OpCode = 0x60
+	Push2 OpCode = 0x61
+	Push3 OpCode = 0x62
+	Push4 OpCode = 0x63
+	Push5 OpCode = 0x64
+	Push6 OpCode = 0x65
+	Push7 OpCode = 0x66
+	Push8 OpCode = 0x67
+	Push9 OpCode = 0x68
+
+	Push10 OpCode = 0x69
+	Push11 OpCode = 0x6a
+	Push12 OpCode = 0x6b
+	Push13 OpCode = 0x6c
+	Push14 OpCode = 0x6d
+	Push15 OpCode = 0x6e
+	Push16 OpCode = 0x6f
+	Push17 OpCode = 0x70
+	Push18 OpCode = 0x71
+	Push19 OpCode = 0x72
 
-// PushOpcodeToBytes maps number of bytes to read from the code and push onto the stack.
+	Push20 OpCode = 0x73
+	Push21 OpCode = 0x74
+	Push22 OpCode = 0x75
+	Push23 OpCode = 0x76
+	Push24 OpCode = 0x77
+	Push25 OpCode = 0x78
+	Push26 OpCode = 0x79
+	Push27 OpCode = 0x7a
+	Push28 OpCode = 0x7b
+	Push29 OpCode = 0x7c
+
+	Push30 OpCode = 0x7d
+	Push31 OpCode = 0x7e
+	Push32 OpCode = 0x7f
+)
+
+// Dup opcodes (0x80-0x8f).
+const (
+	Dup1  OpCode = 0x80
+	Dup2  OpCode = 0x81
+	Dup3  OpCode = 0x82
+	Dup4  OpCode = 0x83
+	Dup5  OpCode = 0x84
+	Dup6  OpCode = 0x85
+	Dup7  OpCode = 0x86
+	Dup8  OpCode = 0x87
+	Dup9  OpCode = 0x88
+	Dup10 OpCode = 0x89
+	Dup11 OpCode = 0x8a
+	Dup12 OpCode = 0x8b
+	Dup13 OpCode = 0x8c
+	Dup14 OpCode = 0x8d
+	Dup15 OpCode = 0x8e
+	Dup16 OpCode = 0x8f
+)
+
+// Swap opcodes (0x90-0x9f).
+const (
+	Swap1  OpCode = 0x90
+	Swap2  OpCode = 0x91
+	Swap3  OpCode = 0x92
+	Swap4  OpCode = 0x93
+	Swap5  OpCode = 0x94
+	Swap6  OpCode = 0x95
+	Swap7  OpCode = 0x96
+	Swap8  OpCode = 0x97
+	Swap9  OpCode = 0x98
+	Swap10 OpCode = 0x99
+	Swap11 OpCode = 0x9a
+	Swap12 OpCode = 0x9b
+	Swap13 OpCode = 0x9c
+	Swap14 OpCode = 0x9d
+	Swap15 OpCode = 0x9e
+	Swap16 OpCode = 0x9f
+)
+
+// Log opcodes (0xa0-0xa4).
+const (
+	Log0 OpCode = 0xa0
+	Log1 OpCode = 0xa1
+	Log2 OpCode = 0xa2
+	Log3 OpCode = 0xa3
+	Log4 OpCode = 0xa4
+)
+
+// Create, call, and halting opcodes (0xf0-0xff).
+const (
+	Create       OpCode = 0xf0
+	Call         OpCode = 0xf1
+	Callcode     OpCode = 0xf2
+	Return       OpCode = 0xf3
+	Delegatecall OpCode = 0xf4
+	Create2      OpCode = 0xf5
+	Staticcall   OpCode = 0xfa
+	Revert       OpCode = 0xfd
+	Invalid      OpCode = 0xfe
+	Selfdestruct OpCode = 0xff
+)
+
+// PushOpcodeToBytes maps a PUSH opcode to the number of immediate bytes
+// it reads from the code and pushes onto the stack.
 var PushOpcodeToBytes = map[OpCode]int{
-	Push1:  1,
-	Push2:  2,
-	Push4:  4,
-	Push6:  6,
-	Push10: 10,
-	Push11: 11,
-	Push32: 32,
+	Push1: 1, Push2: 2, Push3: 3, Push4: 4, Push5: 5,
+	Push6: 6, Push7: 7, Push8: 8, Push9: 9, Push10: 10,
+	Push11: 11, Push12: 12, Push13: 13, Push14: 14, Push15: 15,
+	Push16: 16, Push17: 17, Push18: 18, Push19: 19, Push20: 20,
+	Push21: 21, Push22: 22, Push23: 23, Push24: 24, Push25: 25,
+	Push26: 26, Push27: 27, Push28: 28, Push29: 29, Push30: 30,
+	Push31: 31, Push32: 32,
 }
 
-// NewOpCode tries to convert a byte into an OpCode. It returns an error if the opcodes is unknown.
+// NewOpCode converts a raw code byte into an OpCode. It returns an error
+// if the byte does not correspond to any assigned opcode.
 func NewOpCode(b byte) (OpCode, error) {
-	opcode, ok := opCodeMap[b]
-	if !ok {
-		return 0, errors.New("unknown opcodes")
+	op := OpCode(b)
+
+	switch {
+	case op <= Signextend, // 0x00-0x0b: stop & arithmetic
+		op >= Lt && op <= Sar, // 0x10-0x1d: comparison & bitwise logic
+		op == Sha3,
+		op >= Address && op <= Basefee, // 0x30-0x48: environmental info
+		op >= Pop && op <= Tstore,      // 0x50-0x5d: stack/memory/storage/flow
+		op >= Push0 && op <= Push32,    // 0x5f-0x7f
+		op >= Dup1 && op <= Dup16,      // 0x80-0x8f
+		op >= Swap1 && op <= Swap16,    // 0x90-0x9f
+		op >= Log0 && op <= Log4,       // 0xa0-0xa4
+		op == Create, op == Call, op == Callcode, op == Return,
+		op == Delegatecall, op == Create2, op == Staticcall,
+		op == Revert, op == Invalid, op == Selfdestruct:
+		return op, nil
 	}
-	return opcode, nil
+
+	return 0, errors.New("unknown opcodes")
 }
@@ -1,58 +1,76 @@
 package opcodes
 
-import "math/big"
-
-// PushX reads 'size' bytes from the EVM code starting from the current program counter (PC)
-// and pushes them as a big integer onto the EVM stack.
-//
-// Arguments:
-// pc    - Pointer to the program counter which indicates the current position in the code.
-// stack - Pointer to the EVM stack where all computational values are stored.
-// code  - Byte slice representing the EVM code being executed.
-// size  - Number of bytes to read from the code and push onto the stack.
-//
-// If the bytes to be read exceed the bounds of the code slice, no action is performed.
-// After successfully reading the bytes and pushing them onto the stack, the program counter
-// is updated to the position after the read bytes.
-func PushX(pc *int, stack *[]*big.Int, code []byte, size int) {
-	end := *pc + size
-	if end <= len(code) {
-		value := new(big.Int).SetBytes(code[*pc:end])
-
-		// Prepend the value to the stack.
-		*stack = append(*stack, value)
-
-		*pc = end
+import "github.com/holiman/uint256"
+
+// PushX reads 'size' bytes from the contract code immediately following
+// the current program counter (which still points at the PUSH opcode
+// itself) and pushes them onto the stack as a single big-endian
+// integer. If the immediate would run past the end of the code, the
+// missing bytes are treated as zero, matching how the EVM handles a
+// PUSH whose immediate is truncated by the end of the bytecode.
+func PushX(ctx *ExecutionContext, size int) {
+	start := ctx.PC + 1
+	end := start + size
+
+	// buf is always exactly 'size' bytes so a truncated immediate's
+	// missing trailing bytes read as zero, rather than shifting the
+	// bytes that are present into the wrong (low-order) position.
+	buf := make([]byte, size)
+	if start < len(ctx.Code) {
+		avail := end
+		if avail > len(ctx.Code) {
+			avail = len(ctx.Code)
+		}
+		copy(buf, ctx.Code[start:avail])
 	}
+
+	var v uint256.Int
+	v.SetBytes(buf)
+	ctx.Push(&v)
+	ctx.PC = end
 }
 
-// PopX pops elements from the stack.
-// It takes the program counter, stack, and number of elements to pop as input.
-// It returns the popped elements and a success indicator.
-func PopX(pc *int, stack *[]*big.Int, size int) ([]*big.Int, bool) {
-	if len(*stack) < size {
+// PopX pops 'size' elements from the stack, returning them in the order
+// they were pushed (bottom of the popped group first).
+func PopX(ctx *ExecutionContext, size int) ([]uint256.Int, bool) {
+	if ctx.Stack.Len() < size {
 		return nil, false
 	}
 
-	// Get the last 'size' elements from the stack.
-	elements := (*stack)[len(*stack)-size:]
-
-	// Remove the last 'size' elements from the stack.
-	*stack = (*stack)[:len(*stack)-size]
-	*pc++
+	elements := make([]uint256.Int, size)
+	for i := size - 1; i >= 0; i-- {
+		elements[i] = ctx.Stack.Pop()
+	}
 
 	return elements, true
 }
 
-// popLastElement pops the last element from the stack.
-// It takes the program counter and stack as input.
-// It returns the popped element and a success indicator.
-func popLastElement(pc *int, stack *[]*big.Int) (*big.Int, bool) {
-	if len(*stack) < 1 {
-		return nil, false
+// popLastElement pops a single element from the stack.
+func popLastElement(ctx *ExecutionContext) (uint256.Int, bool) {
+	if ctx.Stack.Len() == 0 {
+		return uint256.Int{}, false
 	}
+	return ctx.Stack.Pop(), true
+}
 
-	elements, _ := PopX(pc, stack, 1)
+// Dup duplicates the n-th element from the top of the stack (1-indexed,
+// as in DUP1..DUP16) and pushes the copy.
+func Dup(ctx *ExecutionContext, n int) bool {
+	v, ok := ctx.Peek(n - 1)
+	if !ok {
+		return false
+	}
+	dup := *v
+	ctx.Push(&dup)
+	return true
+}
 
-	return elements[0], true
+// Swap exchanges the top stack element with the element n positions
+// below it (1-indexed, as in SWAP1..SWAP16).
+func Swap(ctx *ExecutionContext, n int) bool {
+	if ctx.Stack.Len() <= n {
+		return false
+	}
+	ctx.Stack.Swap(n)
+	return true
 }
@@ -0,0 +1,65 @@
+package opcodes
+
+import (
+	"testing"
+
+	"evm-from-scratch-go/state"
+)
+
+func newTestContext(code []byte) *ExecutionContext {
+	return NewExecutionContext(code, CallContext{}, BlockContext{}, TxContext{}, state.NewMemoryState())
+}
+
+func TestPushXReadsImmediateAfterTheOpcodeByte(t *testing.T) {
+	// PUSH1 0x2a - the opcode byte itself must not be mistaken for the
+	// operand.
+	ctx := newTestContext([]byte{byte(Push1), 0x2a})
+
+	PushX(ctx, 1)
+
+	got, ok := ctx.Peek(0)
+	if !ok {
+		t.Fatal("PushX did not push a value")
+	}
+	if got.Uint64() != 0x2a {
+		t.Fatalf("pushed %#x, want 0x2a", got.Uint64())
+	}
+	if ctx.PC != 2 {
+		t.Fatalf("PC = %d, want 2 (opcode byte + 1 immediate byte)", ctx.PC)
+	}
+}
+
+func TestPushXZeroPadsATruncatedImmediate(t *testing.T) {
+	// PUSH2 with only one immediate byte left before the code ends: the
+	// missing trailing byte reads as zero, so the value is 0xff00, not
+	// 0x00ff.
+	ctx := newTestContext([]byte{byte(Push2), 0xff})
+
+	PushX(ctx, 2)
+
+	got, ok := ctx.Peek(0)
+	if !ok {
+		t.Fatal("PushX did not push a value")
+	}
+	if got.Uint64() != 0xff00 {
+		t.Fatalf("pushed %#x, want 0xff00", got.Uint64())
+	}
+	if ctx.PC != 3 {
+		t.Fatalf("PC = %d, want 3 (opcode byte + 2 immediate bytes)", ctx.PC)
+	}
+}
+
+func TestPushXAllImmediateBytesMissing(t *testing.T) {
+	// PUSH1 with no code left at all after the opcode reads as 0.
+	ctx := newTestContext([]byte{byte(Push1)})
+
+	PushX(ctx, 1)
+
+	got, ok := ctx.Peek(0)
+	if !ok {
+		t.Fatal("PushX did not push a value")
+	}
+	if !got.IsZero() {
+		t.Fatalf("pushed %#x, want 0", got.Uint64())
+	}
+}
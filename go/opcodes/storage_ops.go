@@ -0,0 +1,74 @@
+package opcodes
+
+import "github.com/holiman/uint256"
+
+// uint256ToHash renders x as a 32-byte big-endian word, as used for
+// storage keys and values.
+func uint256ToHash(x *uint256.Int) [32]byte {
+	return x.Bytes32()
+}
+
+// HashFromWord renders x as a 32-byte big-endian word, the form used
+// for storage keys and values. It is exported for the gas package,
+// which needs it to key SSTORE's EIP-2929/EIP-2200 bookkeeping.
+func HashFromWord(x *uint256.Int) [32]byte {
+	return uint256ToHash(x)
+}
+
+// ApplySload implements SLOAD: pop a storage key and push the value
+// stored at that key in the executing contract's storage.
+func ApplySload(ctx *ExecutionContext) bool {
+	key, ok := popLastElement(ctx)
+	if !ok {
+		return false
+	}
+	value := ctx.State.GetState(ctx.Call.Address, uint256ToHash(&key))
+	var result uint256.Int
+	result.SetBytes32(value[:])
+	ctx.Push(&result)
+	ctx.PC++
+	return true
+}
+
+// ApplySstore implements SSTORE: pop a storage key and a value, and
+// write the value into the executing contract's storage.
+func ApplySstore(ctx *ExecutionContext) bool {
+	if ctx.Stack.Len() < 2 {
+		return false
+	}
+	key, _ := popLastElement(ctx)
+	value, _ := popLastElement(ctx)
+
+	ctx.State.SetState(ctx.Call.Address, uint256ToHash(&key), uint256ToHash(&value))
+	ctx.PC++
+	return true
+}
+
+// ApplyTload implements TLOAD (EIP-1153): like SLOAD but against
+// transient storage, which is discarded at the end of the transaction.
+func ApplyTload(ctx *ExecutionContext) bool {
+	key, ok := popLastElement(ctx)
+	if !ok {
+		return false
+	}
+	value := ctx.State.GetTransientState(ctx.Call.Address, uint256ToHash(&key))
+	var result uint256.Int
+	result.SetBytes32(value[:])
+	ctx.Push(&result)
+	ctx.PC++
+	return true
+}
+
+// ApplyTstore implements TSTORE (EIP-1153): like SSTORE but against
+// transient storage.
+func ApplyTstore(ctx *ExecutionContext) bool {
+	if ctx.Stack.Len() < 2 {
+		return false
+	}
+	key, _ := popLastElement(ctx)
+	value, _ := popLastElement(ctx)
+
+	ctx.State.SetTransientState(ctx.Call.Address, uint256ToHash(&key), uint256ToHash(&value))
+	ctx.PC++
+	return true
+}
@@ -0,0 +1,63 @@
+package stack
+
+import "github.com/holiman/uint256"
+
+// Stack holds the EVM's 256-bit word stack. It stores uint256.Int by
+// value, so pushing, popping, and duplicating never allocate on the
+// heap the way a slice of *big.Int would.
+type Stack struct {
+	data []uint256.Int
+}
+
+// New creates an empty Stack.
+func New() *Stack {
+	return &Stack{data: make([]uint256.Int, 0, 16)}
+}
+
+// Push pushes a copy of d onto the stack.
+func (st *Stack) Push(d *uint256.Int) {
+	st.data = append(st.data, *d)
+}
+
+// Pop removes and returns the top of the stack by value. The caller
+// must check Len first; popping an empty stack panics.
+func (st *Stack) Pop() uint256.Int {
+	top := len(st.data) - 1
+	v := st.data[top]
+	st.data = st.data[:top]
+	return v
+}
+
+// Len returns the number of elements on the stack.
+func (st *Stack) Len() int {
+	return len(st.data)
+}
+
+// Peek returns a pointer to the top of the stack without removing it,
+// for the in-place mutation pattern used by binary ops:
+//
+//	x := stack.Pop()
+//	y := stack.Peek()
+//	y.Add(&x, y)
+func (st *Stack) Peek() *uint256.Int {
+	return &st.data[len(st.data)-1]
+}
+
+// PeekAt returns a pointer to the n-th element from the top of the
+// stack (0 is the top) without removing it.
+func (st *Stack) PeekAt(n int) *uint256.Int {
+	return &st.data[len(st.data)-1-n]
+}
+
+// Swap exchanges the top of the stack with the element n positions
+// below it (1-indexed, as in SWAP1..SWAP16).
+func (st *Stack) Swap(n int) {
+	top := len(st.data) - 1
+	st.data[top], st.data[top-n] = st.data[top-n], st.data[top]
+}
+
+// Data returns the underlying slice, bottom first. The caller must not
+// retain it across further mutation of the stack.
+func (st *Stack) Data() []uint256.Int {
+	return st.data
+}
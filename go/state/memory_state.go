@@ -0,0 +1,82 @@
+package state
+
+import (
+	"github.com/holiman/uint256"
+	"golang.org/x/crypto/sha3"
+)
+
+// storageKey addresses a single storage slot of a single account.
+type storageKey struct {
+	addr [20]byte
+	key  [32]byte
+}
+
+// MemoryState is a StateDB backed entirely by in-memory maps. It is
+// meant for running isolated bytecode - tests and fixtures - without a
+// real chain behind it; accounts that were never seeded read as empty.
+type MemoryState struct {
+	balances  map[[20]byte]*uint256.Int
+	code      map[[20]byte][]byte
+	storage   map[storageKey][32]byte
+	transient map[storageKey][32]byte
+}
+
+// NewMemoryState creates an empty MemoryState.
+func NewMemoryState() *MemoryState {
+	return &MemoryState{
+		balances:  make(map[[20]byte]*uint256.Int),
+		code:      make(map[[20]byte][]byte),
+		storage:   make(map[storageKey][32]byte),
+		transient: make(map[storageKey][32]byte),
+	}
+}
+
+// SetBalance seeds an account's balance.
+func (s *MemoryState) SetBalance(addr [20]byte, balance *uint256.Int) {
+	s.balances[addr] = balance
+}
+
+// SetCode seeds an account's code.
+func (s *MemoryState) SetCode(addr [20]byte, code []byte) {
+	s.code[addr] = code
+}
+
+func (s *MemoryState) GetBalance(addr [20]byte) *uint256.Int {
+	if b, ok := s.balances[addr]; ok {
+		return new(uint256.Int).Set(b)
+	}
+	return new(uint256.Int)
+}
+
+func (s *MemoryState) GetCode(addr [20]byte) []byte {
+	return s.code[addr]
+}
+
+func (s *MemoryState) GetCodeSize(addr [20]byte) int {
+	return len(s.code[addr])
+}
+
+func (s *MemoryState) GetCodeHash(addr [20]byte) [32]byte {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(s.code[addr])
+
+	var out [32]byte
+	copy(out[:], hash.Sum(nil))
+	return out
+}
+
+func (s *MemoryState) GetState(addr [20]byte, key [32]byte) [32]byte {
+	return s.storage[storageKey{addr, key}]
+}
+
+func (s *MemoryState) SetState(addr [20]byte, key [32]byte, value [32]byte) {
+	s.storage[storageKey{addr, key}] = value
+}
+
+func (s *MemoryState) GetTransientState(addr [20]byte, key [32]byte) [32]byte {
+	return s.transient[storageKey{addr, key}]
+}
+
+func (s *MemoryState) SetTransientState(addr [20]byte, key [32]byte, value [32]byte) {
+	s.transient[storageKey{addr, key}] = value
+}
@@ -0,0 +1,21 @@
+package state
+
+import "github.com/holiman/uint256"
+
+// StateDB is the world-state interface that opcodes needing external
+// account data (SLOAD/SSTORE, BALANCE, EXTCODE*, SELFBALANCE, the
+// transient-storage opcodes from EIP-1153, ...) are executed against.
+// A concrete backend - an in-memory one for isolated bytecode, or a
+// trie-backed one for a full node - satisfies this interface.
+type StateDB interface {
+	GetBalance(addr [20]byte) *uint256.Int
+	GetCode(addr [20]byte) []byte
+	GetCodeSize(addr [20]byte) int
+	GetCodeHash(addr [20]byte) [32]byte
+
+	GetState(addr [20]byte, key [32]byte) [32]byte
+	SetState(addr [20]byte, key [32]byte, value [32]byte)
+
+	GetTransientState(addr [20]byte, key [32]byte) [32]byte
+	SetTransientState(addr [20]byte, key [32]byte, value [32]byte)
+}
@@ -0,0 +1,72 @@
+package tracing
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+
+	"github.com/holiman/uint256"
+
+	"evm-from-scratch-go/opcodes"
+)
+
+// jsonLogEntry is one line of JSONLogger's output. Field names match
+// go-ethereum's structured logger, so existing tooling built against it
+// (evm-trace, evmone's test harnesses) can consume this output too.
+type jsonLogEntry struct {
+	Pc      int      `json:"pc"`
+	Op      string   `json:"op"`
+	Gas     uint64   `json:"gas"`
+	GasCost uint64   `json:"gasCost"`
+	Stack   []string `json:"stack"`
+	Memory  string   `json:"memory"`
+	Depth   int      `json:"depth"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// JSONLogger is a Tracer that writes one JSON object per executed step
+// to w.
+type JSONLogger struct {
+	enc *json.Encoder
+}
+
+// NewJSONLogger creates a JSONLogger writing newline-delimited JSON to w.
+func NewJSONLogger(w io.Writer) *JSONLogger {
+	return &JSONLogger{enc: json.NewEncoder(w)}
+}
+
+func (l *JSONLogger) CaptureStart(from, to [20]byte, input []byte, gas uint64, value *uint256.Int) {
+}
+
+func (l *JSONLogger) CaptureState(pc int, op opcodes.OpCode, gas, cost uint64, stack []uint256.Int, memory []byte, storage Storage, depth int) {
+	l.enc.Encode(jsonLogEntry{
+		Pc:      pc,
+		Op:      opcodes.Mnemonics[op],
+		Gas:     gas,
+		GasCost: cost,
+		Stack:   hexStack(stack),
+		Memory:  "0x" + hex.EncodeToString(memory),
+		Depth:   depth,
+	})
+}
+
+func (l *JSONLogger) CaptureFault(pc int, op opcodes.OpCode, gas, cost uint64, depth int, err error) {
+	l.enc.Encode(jsonLogEntry{
+		Pc:      pc,
+		Op:      opcodes.Mnemonics[op],
+		Gas:     gas,
+		GasCost: cost,
+		Depth:   depth,
+		Error:   err.Error(),
+	})
+}
+
+func (l *JSONLogger) CaptureEnd(output []byte, gasUsed uint64, err error) {}
+
+func hexStack(stack []uint256.Int) []string {
+	out := make([]string, len(stack))
+	for i := range stack {
+		out[i] = "0x" + hex.EncodeToString(stack[i].Bytes())
+	}
+	return out
+}
@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"github.com/holiman/uint256"
+
+	"evm-from-scratch-go/opcodes"
+)
+
+// StructLog is one recorded execution step.
+type StructLog struct {
+	Pc      int
+	Op      string
+	Gas     uint64
+	GasCost uint64
+	Stack   []uint256.Int
+	Memory  []byte
+	Storage Storage
+	Depth   int
+	Err     error
+}
+
+// StructLogger is a Tracer that retains every step in memory, for tests
+// and other in-process inspection that would rather work with Go values
+// than parse JSONLogger's output.
+type StructLogger struct {
+	Logs    []StructLog
+	Output  []byte
+	GasUsed uint64
+	Err     error
+}
+
+// NewStructLogger creates an empty StructLogger.
+func NewStructLogger() *StructLogger {
+	return &StructLogger{}
+}
+
+func (l *StructLogger) CaptureStart(from, to [20]byte, input []byte, gas uint64, value *uint256.Int) {
+}
+
+func (l *StructLogger) CaptureState(pc int, op opcodes.OpCode, gas, cost uint64, stack []uint256.Int, memory []byte, storage Storage, depth int) {
+	l.Logs = append(l.Logs, StructLog{
+		Pc:      pc,
+		Op:      opcodes.Mnemonics[op],
+		Gas:     gas,
+		GasCost: cost,
+		Stack:   append([]uint256.Int(nil), stack...),
+		Memory:  append([]byte(nil), memory...),
+		Storage: storage,
+		Depth:   depth,
+	})
+}
+
+func (l *StructLogger) CaptureFault(pc int, op opcodes.OpCode, gas, cost uint64, depth int, err error) {
+	l.Logs = append(l.Logs, StructLog{
+		Pc:      pc,
+		Op:      opcodes.Mnemonics[op],
+		Gas:     gas,
+		GasCost: cost,
+		Depth:   depth,
+		Err:     err,
+	})
+}
+
+func (l *StructLogger) CaptureEnd(output []byte, gasUsed uint64, err error) {
+	l.Output = output
+	l.GasUsed = gasUsed
+	l.Err = err
+}
@@ -0,0 +1,54 @@
+// Package tracing provides structured execution tracing for the
+// interpreter: a Tracer interface invoked once per executed step, a
+// JSON-streaming logger matching go-ethereum's structured logger
+// format, and an in-memory logger for tests.
+package tracing
+
+import (
+	"github.com/holiman/uint256"
+
+	"evm-from-scratch-go/opcodes"
+)
+
+// Storage is a snapshot of the storage slots a traced execution has
+// written so far, keyed by slot. It reflects only the writes tracing
+// has observed, not an account's full storage, since state.StateDB has
+// no way to enumerate all of an account's slots.
+type Storage map[[32]byte][32]byte
+
+// Tracer observes an interpreter's execution one step at a time. The
+// interpreter invokes its hooks only when a Tracer is configured, so
+// that tracing costs nothing when no one is watching.
+type Tracer interface {
+	// CaptureStart is called once, before the first instruction runs.
+	CaptureStart(from, to [20]byte, input []byte, gas uint64, value *uint256.Int)
+
+	// CaptureState is called before each instruction that passes the
+	// stack-height and gas checks, with the machine state as of just
+	// before it runs.
+	CaptureState(pc int, op opcodes.OpCode, gas, cost uint64, stack []uint256.Int, memory []byte, storage Storage, depth int)
+
+	// CaptureFault is called when an instruction fails. A failure found
+	// before it runs (a stack-height violation, an unknown or
+	// unimplemented opcode, running out of gas) is reported in place of
+	// CaptureState; a failure inside the instruction itself (such as an
+	// invalid jump destination) is reported after its CaptureState.
+	CaptureFault(pc int, op opcodes.OpCode, gas, cost uint64, depth int, err error)
+
+	// CaptureEnd is called once execution halts, successfully or not.
+	CaptureEnd(output []byte, gasUsed uint64, err error)
+}
+
+// NoOpTracer implements Tracer with no-op methods. It gives callers a
+// concrete, always-non-nil Tracer value to pass around when they don't
+// actually want tracing, without special-casing nil themselves.
+type NoOpTracer struct{}
+
+func (NoOpTracer) CaptureStart(from, to [20]byte, input []byte, gas uint64, value *uint256.Int) {}
+
+func (NoOpTracer) CaptureState(pc int, op opcodes.OpCode, gas, cost uint64, stack []uint256.Int, memory []byte, storage Storage, depth int) {
+}
+
+func (NoOpTracer) CaptureFault(pc int, op opcodes.OpCode, gas, cost uint64, depth int, err error) {}
+
+func (NoOpTracer) CaptureEnd(output []byte, gasUsed uint64, err error) {}